@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+)
+
+// releaseReport describes the calculated version against the previous tag.
+// The previous tag is looked up even when existing tags are not used as the baseline.
+func (s *Setup) releaseReport() utils.ReleaseReport {
+	if !s.respectExisting() {
+		s.listExistingTags()
+	}
+
+	latestTagIndex, previousTag := utils.LatestTagIndex(s.GitRepo.Commits, s.GitRepo.Tags)
+	return utils.NewReleaseReport(
+		s.Semver,
+		previousTag,
+		s.Config.TagPrefixes,
+		s.GitRepo.Commits[latestTagIndex+1:],
+		s.Config.Wording,
+		s.Config.Blacklist,
+		s.rulesAt(),
+	)
+}
+
+// publishActions writes the step outputs and summary and annotates anything
+// which may have affected the calculation when running as a GitHub Actions step
+func (s *Setup) publishActions() {
+	report := s.releaseReport()
+	s.annotateActions()
+
+	if err := utils.WriteActionsOutputs(report); err != nil {
+		utils.Error("Unable to write step outputs", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if err := utils.WriteActionsSummary(report, s.GitRepo.SkippedTags); err != nil {
+		utils.Error("Unable to write step summary", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// annotateActions prints warning annotations for a shallow clone and skipped tags. Annotations are workflow commands
+// on stdout, so they are left out when the environment variables are printed there, e.g. for eval "$(semver-gen generate -o env)".
+func (s *Setup) annotateActions() {
+	if params.varOutput != "" && params.varOutputFile == "" {
+		utils.Debug("Skipping annotations, the environment variables are printed to stdout", nil)
+		return
+	}
+
+	if utils.IsShallow(&s.GitRepo) {
+		utils.ActionsWarning("Shallow clone", "Repository history is incomplete, the version may be calculated from a partial history. Use fetch-depth: 0 with actions/checkout.")
+	}
+	for _, tag := range s.GitRepo.SkippedTags {
+		if tag.Reason == utils.SkipReasonNotSemver {
+			continue
+		}
+		utils.ActionsWarning("Skipped tag", "Tag "+tag.Name+" was not used as the baseline: "+tag.Reason)
+	}
+}
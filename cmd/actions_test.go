@@ -0,0 +1,89 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_publishActions(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "Update readme", "Update docs")
+	repo.tag("v0.0.1", repo.hashes[0])
+	repo.tag("latest", repo.hashes[0])
+	dir := repo.dir
+
+	outputPath := filepath.Join(t.TempDir(), "output")
+	summaryPath := filepath.Join(t.TempDir(), "summary")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+
+	assertions.NoError(t, os.Chdir(dir))
+	params = myParams{varExisting: true}
+	s := &Setup{UseLocal: true, LocalConfigFile: filepath.Join(dir, "missing.yaml")}
+	assertions.NoError(t, s.calculate())
+	s.publishActions()
+
+	output, err := os.ReadFile(outputPath)
+	assertions.NoError(t, err)
+	assertions.Contains(t, string(output), "version="+s.getSemver()+"\n")
+	assertions.Contains(t, string(output), "previous_version=0.0.1\n")
+	assertions.Contains(t, string(output), "bump=patch\n")
+	assertions.Contains(t, string(output), "release_needed=true\n")
+
+	summary, err := os.ReadFile(summaryPath)
+	assertions.NoError(t, err)
+	assertions.Contains(t, string(summary), "| Commits since previous version | 2 |")
+	assertions.Contains(t, string(summary), "| `latest` | "+utils.SkipReasonNotSemver+" |", "Non-semver tags should be listed")
+}
+
+func TestSetup_annotateActions(t *testing.T) {
+	originalParams := params
+	defer func() { params = originalParams }()
+
+	s := &Setup{GitRepo: utils.GitRepository{SkippedTags: []utils.SkippedTag{{Name: "v9.9.9", Reason: "unsigned lightweight tag"}}}}
+	annotations := func() string {
+		stdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		s.annotateActions()
+		w.Close()
+		os.Stdout = stdout
+		out, _ := io.ReadAll(r)
+		return string(out)
+	}
+
+	params = myParams{}
+	assertions.Contains(t, annotations(), "::warning title=Skipped tag::Tag v9.9.9")
+
+	params = myParams{varOutput: utils.OutputEnv, varOutputFile: filepath.Join(t.TempDir(), "semver.env")}
+	assertions.Contains(t, annotations(), "::warning title=Skipped tag::Tag v9.9.9", "variables written to a file leave stdout free")
+
+	params = myParams{varOutput: utils.OutputEnv}
+	assertions.Empty(t, annotations(), "annotations would corrupt the variables printed to stdout")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// backfillCmd represents the backfill command
+var backfillCmd = &cobra.Command{
+	Use:   "backfill [flags]",
+	Short: "Creates tags for the historical versions of the repository",
+	Long: `Replays the semantic version calculation over the entire history and tags every commit changing the version,
+	or only the ones changing it by at least --level (e.g. minor), so later runs start from the latest tag.
+	Tags follow the tag section of the config. Nothing is created until --apply is set, the tags are only listed.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.backfill(); err != nil {
+			utils.Critical("Unable to backfill tags", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// backfill tags the commits at which the calculated version changes by at least the requested level
+func (s *Setup) backfill() error {
+	level, err := utils.ParseBump(params.varBackfillLevel)
+	if err != nil {
+		return err
+	}
+	changes, err := s.versionHistory()
+	if err != nil {
+		return err
+	}
+
+	nameTemplate, messageTemplate := s.tagTemplates()
+	signer, err := utils.LoadSigner(s.Config.Signing)
+	if err != nil {
+		return err
+	}
+	dryRun := !params.varApply
+
+	created := 0
+	for _, change := range changes {
+		// Versions taken from existing tags are tagged already
+		if change.Tag != "" || utils.CompareBump(change.Previous, change.Version) < level {
+			continue
+		}
+
+		name, err := utils.RenderVersionTemplate(nameTemplate, change.Version)
+		if err != nil {
+			return err
+		}
+		message, err := utils.RenderVersionTemplate(messageTemplate, change.Version)
+		if err != nil {
+			return err
+		}
+		err = utils.CreateTagAt(&s.GitRepo, name, change.Hash, message, signer, dryRun)
+		switch {
+		case errors.Is(err, utils.ErrTagExists):
+			fmt.Printf("TAG %s %s exists, skipped\n", name, change.Hash[:7])
+			continue
+		case err != nil:
+			return err
+		}
+		created++
+		fmt.Printf("TAG %s %s %s\n", name, change.Hash[:7], change.Subject)
+	}
+
+	if dryRun {
+		fmt.Printf("BACKFILL %d tags to create, run with --apply to create them\n", created)
+	} else {
+		fmt.Printf("BACKFILL %d tags created\n", created)
+	}
+	return nil
+}
+
+func init() {
+	backfillCmd.Flags().StringVar(&params.varBackfillLevel, "level", utils.BumpPatch.String(), "Only tag versions changing at least this level: patch, release, minor or major")
+	backfillCmd.Flags().BoolVar(&params.varApply, "apply", false, "Create the listed tags")
+	rootCmd.AddCommand(backfillCmd)
+}
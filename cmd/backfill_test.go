@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_backfill(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	configFile := ""
+	newRepository := func(t *testing.T) *git.Repository {
+		repo := initTestRepository(t, "Initial commit", "fix: typo", "feat: login", "fix: crash", "Update docs")
+		configFile = filepath.Join(repo.dir, "semver.yaml")
+		assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+force:
+  strict: true
+wording:
+  patch:
+    - fix
+  minor:
+    - feat
+`), 0o600))
+		assertions.NoError(t, os.Chdir(repo.dir))
+		return repo.handler
+	}
+	tagNames := func(handler *git.Repository) []string {
+		var names []string
+		tags, _ := handler.Tags()
+		_ = tags.ForEach(func(ref *plumbing.Reference) error {
+			names = append(names, ref.Name().Short())
+			return nil
+		})
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		params   myParams
+		wantTags []string
+		wantErr  bool
+	}{
+		{name: "Dry run by default", params: myParams{varBackfillLevel: "patch"}},
+		{name: "Every version", params: myParams{varBackfillLevel: "patch", varApply: true}, wantTags: []string{"v0.0.1", "v0.1.1", "v0.1.2"}},
+		{name: "Minor versions only", params: myParams{varBackfillLevel: "minor", varApply: true}, wantTags: []string{"v0.1.1"}},
+		{name: "Unknown level", params: myParams{varBackfillLevel: "huge"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newRepository(t)
+			params = tt.params
+			params.varExisting = true
+			s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+			err := s.backfill()
+			if tt.wantErr {
+				assertions.Error(t, err)
+				return
+			}
+			assertions.NoError(t, err)
+			assertions.ElementsMatch(t, tt.wantTags, tagNames(handler))
+
+			// Later runs start from the created tags and leave them in place
+			assertions.NoError(t, s.backfill())
+			assertions.ElementsMatch(t, tt.wantTags, tagNames(handler))
+			assertions.NoError(t, s.calculate())
+			assertions.Equal(t, "0.1.2", s.getSemver())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// bumpFilesCmd represents the bump-files command
+var bumpFilesCmd = &cobra.Command{
+	Use:   "bump-files [flags]",
+	Short: "Updates the version in the files listed in the configuration",
+	Long: `Writes the generated semantic version to the manifests listed in the files section of the configuration,
+	e.g. package.json, Chart.yaml, pyproject.toml, Cargo.toml, pom.xml or a Go version constant.
+	Files are rewritten in place, only the version value changes.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.bumpFiles(); err != nil {
+			utils.Critical("Unable to update files", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// bumpFiles calculates the semantic version and writes it to the configured files
+func (s *Setup) bumpFiles() error {
+	if err := s.calculate(); err != nil {
+		return err
+	}
+	if _, err := s.updateVersionFiles(); err != nil {
+		return err
+	}
+	fmt.Println("SEMVER", s.getSemver())
+	return nil
+}
+
+// updateVersionFiles writes the calculated version to the configured files and returns the paths of the changed ones
+func (s *Setup) updateVersionFiles() ([]string, error) {
+	if len(s.Config.Files) == 0 {
+		return nil, errors.New("no files configured, list them in the files section of the configuration")
+	}
+
+	var changed []string
+	for _, file := range s.Config.Files {
+		value := s.getSemver()
+		if file.Template != "" {
+			var err error
+			if value, err = utils.RenderVersionTemplate(file.Template, s.Semver); err != nil {
+				return changed, err
+			}
+		}
+
+		updated, err := utils.UpdateVersionFile(file, value, params.varDryRun)
+		if err != nil {
+			return changed, err
+		}
+		if updated {
+			changed = append(changed, file.Path)
+			fmt.Println("FILE", file.Path, "updated to", value)
+		} else {
+			fmt.Println("FILE", file.Path, "already at", value)
+		}
+	}
+	return changed, nil
+}
+
+func init() {
+	bumpFilesCmd.Flags().BoolVar(&params.varDryRun, "dry-run", false, "Show the changes without writing the files")
+	rootCmd.AddCommand(bumpFilesCmd)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_bumpFiles(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	dir := initTestRepository(t, "Initial commit", "fix: typo").dir
+
+	packageJSON := "{\n  \"name\": \"app\",\n  \"version\": \"0.0.0\"\n}\n"
+	manifest := "name: zürich\nimage: {repository: ü, tag: v0.0.0}\n"
+	assertions.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(packageJSON), 0o600))
+	assertions.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0o600))
+	configFile := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+force:
+  strict: true
+wording:
+  patch:
+    - fix
+files:
+  - path: package.json
+  - path: manifest.yaml
+    yaml_path: image.tag
+    template: "v{{ .Version }}"
+`), 0o600))
+	emptyConfig := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(emptyConfig, []byte("version: 1\n"), 0o600))
+	assertions.NoError(t, os.Chdir(dir))
+
+	t.Run("Dry run leaves the files", func(t *testing.T) {
+		params = myParams{varExisting: true, varDryRun: true}
+		s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+		assertions.NoError(t, s.bumpFiles())
+		content, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+		assertions.Equal(t, packageJSON, string(content))
+	})
+
+	t.Run("Files are updated", func(t *testing.T) {
+		params = myParams{varExisting: true}
+		s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+		assertions.NoError(t, s.bumpFiles())
+		assertions.Equal(t, "0.0.1", s.getSemver())
+		content, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+		assertions.Equal(t, "{\n  \"name\": \"app\",\n  \"version\": \"0.0.1\"\n}\n", string(content))
+		content, _ = os.ReadFile(filepath.Join(dir, "manifest.yaml"))
+		assertions.Equal(t, "name: zürich\nimage: {repository: ü, tag: v0.0.1}\n", string(content))
+
+		// A second run finds the files up to date
+		changed, err := s.updateVersionFiles()
+		assertions.NoError(t, err)
+		assertions.Empty(t, changed)
+	})
+
+	t.Run("No files configured", func(t *testing.T) {
+		params = myParams{varExisting: true}
+		s := &Setup{UseLocal: true, LocalConfigFile: emptyConfig}
+		assertions.Error(t, s.bumpFiles())
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// changelogCmd represents the changelog command
+var changelogCmd = &cobra.Command{
+	Use:   "changelog [flags]",
+	Short: "Generates changelog of the changes since the previous tag",
+	Long: `Renders Markdown release notes of the commits since the previous tag, grouped into
+	Breaking / Features / Fixes / Other sections by the keywords matched in the configuration.
+	With --file the notes are added as a new version section of a Keep a Changelog file.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.changelog(); err != nil {
+			utils.Critical("Unable to generate changelog", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// changelog calculates the semantic version and prints the changes since the previous tag,
+// or adds them to the changelog file when one is set
+func (s *Setup) changelog() error {
+	if err := s.calculate(); err != nil {
+		return err
+	}
+
+	changelog := utils.BuildChangelog(
+		s.getSemver(),
+		s.releaseReport().Commits,
+		s.Config.Wording,
+		s.Config.Blacklist,
+		s.rulesAt(),
+		s.Config.Changelog.Titles,
+		s.repositoryWebURL(),
+	)
+
+	file := s.Config.Changelog.File
+	if params.varChangelogFile != "" {
+		file = params.varChangelogFile
+	}
+	if file != "" {
+		return s.updateChangelogFile(file, changelog)
+	}
+
+	templateFile := s.Config.Changelog.Template
+	if params.varChangelogTemplate != "" {
+		templateFile = params.varChangelogTemplate
+	}
+	rendered, err := utils.RenderChangelog(changelog, templateFile)
+	if err != nil {
+		return err
+	}
+	fmt.Print(rendered)
+	return nil
+}
+
+// updateChangelogFile adds the changelog as a new version section of the Keep a Changelog file
+func (s *Setup) updateChangelogFile(file string, changelog utils.Changelog) error {
+	nameTemplate, _ := s.tagTemplates()
+	tagName, err := utils.RenderVersionTemplate(nameTemplate, s.Semver)
+	if err != nil {
+		return err
+	}
+	_, previousTag := utils.LatestTagIndex(s.GitRepo.Commits, s.GitRepo.Tags)
+
+	updated, err := utils.UpdateChangelogFile(file, changelog, utils.ChangelogRelease{
+		Tag:           tagName,
+		PreviousTag:   previousTag,
+		RepositoryURL: s.repositoryWebURL(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if updated {
+		fmt.Println("CHANGELOG", file, "updated with", changelog.Version)
+	} else {
+		fmt.Println("CHANGELOG", file, "already contains", changelog.Version)
+	}
+	return nil
+}
+
+// repositoryWebURL returns the web URL of the repository used for commit links
+func (s *Setup) repositoryWebURL() string {
+	if !s.UseLocal {
+		return utils.RepositoryWebURL(s.RepositoryName)
+	}
+	if s.GitRepo.Handler == nil {
+		return ""
+	}
+	remote, err := s.GitRepo.Handler.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return utils.RepositoryWebURL(remote.Config().URLs[0])
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&params.varChangelogTemplate, "template", "", "Path to the changelog template")
+	changelogCmd.Flags().StringVar(&params.varChangelogFile, "file", "", "Add a new version section to the Keep a Changelog file (e.g. CHANGELOG.md) instead of printing")
+	rootCmd.AddCommand(changelogCmd)
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manages the configuration file",
+	Long: `Commands working with the configuration file given with --config, or found in the current and parent directories.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [flags]",
+	Short: "Validates the configuration file",
+	Long: `Reports unknown keys, values of the wrong type, empty wording lists, keywords configured for several levels
+	or containing blacklisted terms and invalid force.commit hashes, with the file and line of each issue.
+	The same checks run whenever the configuration is loaded: errors (unknown keys, wrong types, unsupported versions)
+	stop every command, the other issues are logged as warnings.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.validateConfig(); err != nil {
+			utils.Critical("Configuration is not valid", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// configMigrateCmd represents the config migrate command
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [flags]",
+	Short: "Rewrites the configuration file in the newest schema version",
+	Long: `Upgrades the YAML configuration file to the newest schema version, keeping its comments.
+	Older versions keep working as they are upgraded in memory when loaded, newer versions than supported are rejected.
+	With --dry-run the migrated configuration is printed instead of written.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.migrateConfig(); err != nil {
+			utils.Critical("Unable to migrate configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init [flags]",
+	Short: "Writes a commented configuration file from a built-in preset",
+	Long: `Writes the configuration file given with --config, or semver.yaml in the current directory, from one of the built-in presets:
+	conventional, angular, gitmoji or simple. With --inspect the tags of the local repository are looked at
+	to prefill tag_prefixes and to suggest a force.commit when there are no version tags yet.
+	An existing file is only overwritten with --force.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.initConfig(); err != nil {
+			utils.Critical("Unable to write configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// initConfig writes the configuration file of the preset, completed with what the repository tells about itself
+func (s *Setup) initConfig() error {
+	if !s.ConfigSet {
+		// The configuration found in a parent directory is not the one to write
+		s.LocalConfigFile = utils.ConfigFileNames[0]
+	}
+	if _, err := os.Stat(s.LocalConfigFile); err == nil && !params.varForce {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", s.LocalConfigFile)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if ext := strings.ToLower(filepath.Ext(s.LocalConfigFile)); ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("presets are written as YAML, %s needs a .yaml extension", s.LocalConfigFile)
+	}
+
+	var facts utils.RepositoryFacts
+	if params.varInspect {
+		// Resolve the path before PrepareRepository changes into the repository
+		path, err := filepath.Abs(s.LocalConfigFile)
+		if err != nil {
+			return err
+		}
+		s.LocalConfigFile = path
+
+		s.GitRepo = utils.GitRepository{UseLocal: true}
+		if err := utils.PrepareRepository(&s.GitRepo); err != nil {
+			return err
+		}
+		if facts, err = utils.InspectRepository(&s.GitRepo); err != nil {
+			return err
+		}
+	}
+
+	content, err := utils.RenderPresetConfig(params.varPreset, facts)
+	if err != nil {
+		return err
+	}
+	// #nosec G306 -- the configuration is a regular repository file
+	if err := os.WriteFile(s.LocalConfigFile, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("unable to write %s: %w", s.LocalConfigFile, err)
+	}
+
+	fmt.Println("CONFIG", s.LocalConfigFile, "written with the", params.varPreset, "preset")
+	if len(facts.TagPrefixes) > 0 {
+		fmt.Println("CONFIG tag prefixes", strings.Join(facts.TagPrefixes, " "))
+	}
+	if facts.LatestTag != "" {
+		fmt.Println("CONFIG latest version tag", facts.LatestTag)
+	}
+	return nil
+}
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show [flags]",
+	Short: "Prints the effective configuration",
+	Long: `Prints the configuration as it is used: the file given with --config, or the first of semver.yaml, .semver.yaml
+	and .github/semver.yaml found in the current directory and its parents up to the repository root,
+	with the SEMVER_* environment variables and the --set overrides applied on top of it.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.showConfig(); err != nil {
+			utils.Critical("Unable to read configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// showConfig prints the merged configuration, preceded by the sources it was merged from
+func (s *Setup) showConfig() error {
+	sources := s.configSources()
+	if _, err := os.Stat(s.LocalConfigFile); errors.Is(err, fs.ErrNotExist) {
+		sources.File = ""
+	}
+	content, applied, err := utils.MergeConfig(sources)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		applied = []string{"defaults"}
+	}
+	fmt.Println("# Sources:", strings.Join(applied, ", "))
+	fmt.Print(string(content))
+	return nil
+}
+
+// validateConfig prints every issue of the configuration file
+func (s *Setup) validateConfig() error {
+	issues, err := utils.ValidateConfig(s.LocalConfigFile)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Println("CONFIG", issue)
+	}
+	if errs, _ := utils.SplitConfigIssues(issues); len(errs) > 0 {
+		return fmt.Errorf("%d errors found in %s", len(errs), s.LocalConfigFile)
+	}
+
+	// Catch anything the schema does not cover, and invalid environment variables or --set overrides
+	if _, err := utils.LoadConfig(s.configSources()); err != nil {
+		return err
+	}
+	fmt.Println("VALID", s.LocalConfigFile)
+	return nil
+}
+
+// migrateConfig rewrites the configuration file in the current schema version
+func (s *Setup) migrateConfig() error {
+	content, version, err := utils.MigrateConfig(s.LocalConfigFile)
+	if err != nil {
+		return err
+	}
+	if version == utils.CurrentConfigVersion {
+		fmt.Println("CONFIG", s.LocalConfigFile, "already uses version", version)
+		return nil
+	}
+	if params.varDryRun {
+		fmt.Print(string(content))
+		return nil
+	}
+
+	info, err := os.Stat(s.LocalConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.LocalConfigFile, content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("unable to write %s: %w", s.LocalConfigFile, err)
+	}
+	fmt.Println("CONFIG", s.LocalConfigFile, "migrated from version", version, "to", utils.CurrentConfigVersion)
+	return nil
+}
+
+func init() {
+	configMigrateCmd.Flags().BoolVar(&params.varDryRun, "dry-run", false, "Print the migrated configuration instead of writing it")
+	configInitCmd.Flags().StringVar(&params.varPreset, "preset", "conventional", "Preset to start from: "+strings.Join(utils.PresetNames(), ", "))
+	configInitCmd.Flags().BoolVar(&params.varInspect, "inspect", false, "Prefill tag_prefixes and force from the tags of the local repository")
+	configInitCmd.Flags().BoolVar(&params.varForce, "force", false, "Overwrite an existing configuration file")
+	configCmd.AddCommand(configInitCmd, configShowCmd, configValidateCmd, configMigrateCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_validateConfig(t *testing.T) {
+	utils.InitLogger(false)
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	assertions.NoError(t, os.WriteFile(valid, []byte("version: 1\nwording:\n  patch: [fix]\n"), 0o600))
+	invalid := filepath.Join(dir, "invalid.yaml")
+	assertions.NoError(t, os.WriteFile(invalid, []byte("version: 1\nwordings:\n  patch: [fix]\n"), 0o600))
+	warnings := filepath.Join(dir, "warnings.yaml")
+	assertions.NoError(t, os.WriteFile(warnings, []byte("version: 1\nforce:\n  commit: abc123\nwording:\n  patch: [update]\n  minor: [update]\n"), 0o600))
+	missing := filepath.Join(dir, "missing.yaml")
+
+	tests := []struct {
+		name        string
+		file        string
+		wantErr     bool
+		wantReadErr bool
+	}{
+		{name: "Valid file", file: valid},
+		{name: "Warnings only", file: warnings},
+		{name: "Invalid file", file: invalid, wantErr: true, wantReadErr: true},
+		{name: "Missing file", file: missing, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Setup{LocalConfigFile: tt.file}
+			if tt.wantErr {
+				assertions.Error(t, s.validateConfig())
+			} else {
+				assertions.NoError(t, s.validateConfig())
+			}
+
+			// Loading is as strict, but a missing file falls back to defaults
+			err := s.readConfig()
+			if tt.wantReadErr {
+				var configErr *utils.ConfigError
+				assertions.ErrorAs(t, err, &configErr)
+				return
+			}
+			assertions.NoError(t, err)
+			assertions.NotNil(t, s.Config)
+		})
+	}
+}
+
+func TestSetup_migrateConfig(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+
+	file := filepath.Join(t.TempDir(), "semver.yaml")
+	original := "wording:\n  patch:\n    - fix\n"
+	assertions.NoError(t, os.WriteFile(file, []byte(original), 0o600))
+	s := &Setup{LocalConfigFile: file}
+
+	params = myParams{varDryRun: true}
+	assertions.NoError(t, s.migrateConfig())
+	content, _ := os.ReadFile(file)
+	assertions.Equal(t, original, string(content), "dry run leaves the file untouched")
+
+	params = myParams{}
+	assertions.NoError(t, s.migrateConfig())
+	content, _ = os.ReadFile(file)
+	assertions.Equal(t, "version: 1\n"+original, string(content))
+
+	// Up to date files are left alone
+	assertions.NoError(t, s.migrateConfig())
+
+	assertions.NoError(t, os.WriteFile(file, []byte("version: 9\n"), 0o600))
+	assertions.ErrorIs(t, s.migrateConfig(), utils.ErrUnsupportedConfigVersion)
+	assertions.Error(t, s.readConfig())
+}
+
+func TestSetup_initConfig(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	cwd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(cwd) }()
+
+	repo := initTestRepository(t, "Initial commit")
+	repo.tag("app-1.4.0", repo.hashes[0])
+	assertions.NoError(t, os.Chdir(repo.dir))
+
+	params = myParams{varPreset: "angular", varInspect: true}
+	s := &Setup{LocalConfigFile: "semver.yaml"}
+	assertions.NoError(t, s.initConfig())
+	assertions.NoError(t, s.readConfig())
+	assertions.Equal(t, []string{"app-"}, s.Config.TagPrefixes)
+	assertions.Equal(t, utils.ConfigPresets["angular"].Wording, s.Config.Wording)
+
+	// Existing files are only overwritten with --force
+	s = &Setup{LocalConfigFile: "semver.yaml"}
+	params = myParams{varPreset: "simple"}
+	assertions.Error(t, s.initConfig())
+	params.varForce = true
+	assertions.NoError(t, s.initConfig())
+	assertions.NoError(t, s.readConfig())
+	assertions.Empty(t, s.Config.TagPrefixes, "nothing is prefilled without --inspect")
+	assertions.False(t, s.Config.Force.Strict)
+
+	params.varPreset = "unknown"
+	assertions.Error(t, s.initConfig())
+	params.varPreset = "simple"
+	s = &Setup{LocalConfigFile: "semver.toml", ConfigSet: true}
+	assertions.Error(t, s.initConfig())
+
+	// The configuration of a parent directory is left alone
+	assertions.NoError(t, os.Mkdir("service", 0o750))
+	assertions.NoError(t, os.Chdir("service"))
+	params = myParams{varPreset: "simple"}
+	s = &Setup{LocalConfigFile: filepath.Join("..", "semver.yaml")}
+	assertions.NoError(t, s.initConfig())
+	assertions.FileExists(t, "semver.yaml")
+}
+
+func TestSetup_showConfig(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	t.Setenv("SEMVER_FORCE_STRICT", "true")
+
+	file := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(file, []byte("wording:\n  patch: [fix]\n"), 0o600))
+
+	params = myParams{varSet: []string{"wording.major=breaking"}}
+	s := &Setup{LocalConfigFile: file}
+	assertions.NoError(t, s.showConfig())
+	assertions.NoError(t, s.readConfig())
+	assertions.True(t, s.Config.Force.Strict)
+	assertions.Equal(t, []string{"breaking"}, s.Config.Wording.Major)
+
+	// Overrides apply without a file as well
+	s = &Setup{LocalConfigFile: filepath.Join(t.TempDir(), "semver.yaml")}
+	assertions.NoError(t, s.showConfig())
+	assertions.NoError(t, s.readConfig())
+	assertions.Equal(t, []string{"breaking"}, s.Config.Wording.Major)
+
+	params = myParams{varSet: []string{"wording.majr=breaking"}}
+	assertions.Error(t, s.showConfig())
+	assertions.Error(t, s.readConfig())
+}
@@ -16,6 +16,13 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -24,14 +31,123 @@ var generateCmd = &cobra.Command{
 	Use:   "generate [flags]",
 	Short: "Generates semantic version",
 	Long: `Semantic version generation using your configuration file and fuzzy matching of git commit messages.
+	With --output the version is written as SEMVER_* environment variables (dotenv or env format),
+	to stdout or to the --output-file, so other jobs can consume it without parsing the output.
+	--ref calculates the version as of any commit, tag or branch, --from and --to report the bump over a range.
 	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		repo.Generate = true
 		repo.setupCobra()
+		repo.Ref = params.varRef
+		// Remote repositories are cloned and entered, keep the output file relative to the caller
+		if params.varOutputFile != "" {
+			if path, err := filepath.Abs(params.varOutputFile); err == nil {
+				params.varOutputFile = path
+			}
+		}
+		if params.varFrom != "" || params.varTo != "" {
+			utils.InitLogger(params.varDebug)
+			if err := repo.generateRange(); err != nil {
+				utils.Critical("Unable to calculate the version range", map[string]interface{}{
+					"error": err.Error(),
+				})
+				os.Exit(1)
+			}
+			return
+		}
 		main()
 	},
 }
 
+// generateRange calculates the versions at both ends of the --from..--to range and the bump between them,
+// listing the level each commit of the range triggers
+func (s *Setup) generateRange() error {
+	if params.varFrom == "" {
+		return errors.New("--to requires --from")
+	}
+	to := params.varTo
+	if to == "" {
+		to = s.Ref
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	if err := s.prepare(); err != nil {
+		return err
+	}
+	report, err := s.rangeReport(params.varFrom, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("FROM %s (%s)\n", report.PreviousVersionString(), params.varFrom)
+	fmt.Printf("TO %s (%s)\n", utils.FormatSemver(report.Version), to)
+	fmt.Println("BUMP", report.Bump())
+	for i, commit := range report.Commits {
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		fmt.Printf("COMMIT %s %-7s %s\n", commit.Hash[:7], report.Bumps[i], subject)
+	}
+	fmt.Println("SEMVER", s.getSemver())
+	return nil
+}
+
+// rangeReport calculates the version at both revisions of the prepared repository, the report
+// describing the version at `to` against the version at `from` and the commits in between
+func (s *Setup) rangeReport(from string, to string) (utils.ReleaseReport, error) {
+	fromHash, err := utils.ResolveRevision(&s.GitRepo, from)
+	if err != nil {
+		return utils.ReleaseReport{}, err
+	}
+	toHash, err := utils.ResolveRevision(&s.GitRepo, to)
+	if err != nil {
+		return utils.ReleaseReport{}, err
+	}
+	commits, err := utils.CommitRange(&s.GitRepo, fromHash, toHash)
+	if err != nil {
+		return utils.ReleaseReport{}, err
+	}
+
+	s.GitRepo.Commit = fromHash
+	s.compute()
+	previous := s.Semver
+
+	s.GitRepo.Commit = toHash
+	s.compute()
+
+	report := utils.NewReleaseReport(s.Semver, "", s.Config.TagPrefixes, commits, s.Config.Wording, s.Config.Blacklist, s.rulesAt())
+	report.PreviousTag = from
+	report.PreviousVersion = previous
+	return report, nil
+}
+
+// writeEnvOutput writes the version as environment variables to the output file, or prints them when none is set
+func (s *Setup) writeEnvOutput() error {
+	format := params.varOutput
+	if format == "" {
+		format = utils.OutputDotenv
+	}
+	content, err := utils.RenderEnvFile(s.releaseReport(), format)
+	if err != nil {
+		return err
+	}
+
+	if params.varOutputFile == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := utils.WriteEnvFile(params.varOutputFile, content); err != nil {
+		return err
+	}
+	fmt.Println("SEMVER", s.getSemver())
+	return nil
+}
+
 func init() {
+	generateCmd.Flags().StringVarP(&params.varOutput, "output", "o", "", "Write the version as environment variables: dotenv (GitLab artifacts:reports:dotenv) or env (shell)")
+	generateCmd.Flags().StringVar(&params.varOutputFile, "output-file", "", "File to write the environment variables to, dotenv format unless --output is set")
+	generateCmd.Flags().StringVar(&params.varRef, "ref", "", "Calculate the version as of a commit, tag or branch instead of HEAD")
+	generateCmd.Flags().StringVar(&params.varFrom, "from", "", "Start of the range (exclusive) to report the bump over")
+	generateCmd.Flags().StringVar(&params.varTo, "to", "", "End of the range to report the bump over, defaults to --ref or HEAD")
 	rootCmd.AddCommand(generateCmd)
 }
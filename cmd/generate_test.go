@@ -0,0 +1,193 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_writeEnvOutput(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	dir := initTestRepository(t, "Initial commit").dir
+
+	assertions.NoError(t, os.Chdir(dir))
+	outputFile := filepath.Join(t.TempDir(), "semver.env")
+	params = myParams{varOutputFile: outputFile}
+	s := &Setup{UseLocal: true, LocalConfigFile: filepath.Join(dir, "missing.yaml")}
+	assertions.NoError(t, s.calculate())
+	assertions.NoError(t, s.writeEnvOutput())
+
+	content, err := os.ReadFile(outputFile)
+	assertions.NoError(t, err)
+	assertions.Contains(t, string(content), "SEMVER_VERSION="+s.getSemver()+"\n", "dotenv should be the default format")
+	assertions.Contains(t, string(content), "SEMVER_PREVIOUS_VERSION=\n")
+
+	params = myParams{varOutput: "xml"}
+	assertions.Error(t, s.writeEnvOutput())
+}
+
+func TestSetup_generateAtRevisions(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "fix: typo", "feat: login", "fix: crash")
+	repo.tag("v1.0.0", repo.hashes[0])
+	dir := repo.dir
+	configFile := filepath.Join(dir, "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+force:
+  strict: true
+wording:
+  patch:
+    - fix
+  minor:
+    - feat
+`), 0o600))
+	assertions.NoError(t, os.Chdir(dir))
+
+	t.Run("Version at a revision", func(t *testing.T) {
+		tests := []struct {
+			ref     string
+			want    string
+			wantErr bool
+		}{
+			{ref: "", want: "1.1.2"},
+			{ref: "v1.0.0", want: "1.0.0"},
+			{ref: repo.hashes[1].String()[:7], want: "1.0.1"},
+			{ref: "HEAD~1", want: "1.1.1"},
+			{ref: "missing", wantErr: true},
+		}
+		for _, tt := range tests {
+			params = myParams{varExisting: true}
+			s := &Setup{UseLocal: true, LocalConfigFile: configFile, Ref: tt.ref}
+			err := s.calculate()
+			if tt.wantErr {
+				assertions.Error(t, err, tt.ref)
+				continue
+			}
+			assertions.NoError(t, err, tt.ref)
+			assertions.Equal(t, tt.want, s.getSemver(), tt.ref)
+		}
+	})
+
+	t.Run("Range report", func(t *testing.T) {
+		params = myParams{varExisting: true}
+		s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+		assertions.NoError(t, s.prepare())
+
+		report, err := s.rangeReport(repo.hashes[1].String()[:8], "HEAD")
+		assertions.NoError(t, err)
+		assertions.Equal(t, "1.0.1", report.PreviousVersionString())
+		assertions.Equal(t, "1.1.2", utils.FormatSemver(report.Version))
+		assertions.Equal(t, utils.BumpMinor, report.Bump())
+		assertions.Equal(t, []utils.Bump{utils.BumpMinor, utils.BumpPatch}, report.Bumps)
+
+		report, err = s.rangeReport("v1.0.0", repo.hashes[1].String())
+		assertions.NoError(t, err)
+		assertions.Equal(t, utils.BumpPatch, report.Bump())
+		assertions.Len(t, report.Commits, 1)
+	})
+
+	t.Run("Range flags", func(t *testing.T) {
+		params = myParams{varExisting: true, varTo: "HEAD"}
+		s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+		assertions.Error(t, s.generateRange(), "--to requires --from")
+
+		params = myParams{varExisting: true, varFrom: "v1.0.0"}
+		s = &Setup{UseLocal: true, LocalConfigFile: configFile, Ref: "HEAD~1"}
+		assertions.NoError(t, s.generateRange())
+		assertions.Equal(t, "1.1.1", s.getSemver(), "--to defaults to --ref")
+	})
+}
+
+func TestSetup_remoteConfig(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	// The remote repository only gets its configuration with the last commit
+	repo := initTestRepository(t, "Initial commit", "feat: login")
+	repo.tag("v1.0.0", repo.hashes[0])
+	repo.commit("fix: crash", map[string]string{
+		"semver.yaml": "version: 1\nforce:\n  strict: true\nwording:\n  patch:\n    - fix\n  minor:\n    - feat\n",
+		"broken.yaml": "version: 1\nwordin:\n  patch:\n    - fix\n",
+		"org.yaml":    "version: 1\nwording:\n  patch:\n    - fix\n  minor:\n    - feat\n",
+	})
+	remote := repo.dir
+
+	// The local configuration only knows feat, as a patch
+	localConfig := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(localConfig, []byte("version: 1\nforce:\n  strict: true\nwording:\n  patch:\n    - feat\n"), 0o600))
+	// A local configuration can extend a file of the remote repository, it is read once cloned
+	extendingConfig := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(extendingConfig, []byte("version: 1\nextends:\n  path: org.yaml\n  repository: true\nforce:\n  strict: true\n"), 0o600))
+
+	tests := []struct {
+		name         string
+		remoteConfig bool
+		configSet    bool
+		configFile   string
+		ref          string
+		want         string
+		wantErr      bool
+	}{
+		{name: "Local configuration", configFile: localConfig, want: "1.0.1"},
+		{name: "Configuration of the repository", remoteConfig: true, configFile: localConfig, want: "1.1.2"},
+		{name: "Local fallback at a commit without configuration", remoteConfig: true, configFile: localConfig, ref: "HEAD~1", want: "1.0.1"},
+		{name: "Local configuration extending a file of the repository", configFile: extendingConfig, want: "1.1.2"},
+		{name: "Relative config path within the repository", remoteConfig: true, configSet: true, configFile: "broken.yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() { _ = os.Chdir(currentDir) }()
+			params = myParams{varExisting: true}
+			s := &Setup{
+				RepositoryName:   remote,
+				RepositoryBranch: "main",
+				LocalConfigFile:  tt.configFile,
+				ConfigSet:        tt.configSet,
+				RemoteConfig:     tt.remoteConfig,
+				Ref:              tt.ref,
+			}
+			err := s.calculate()
+			if tt.wantErr {
+				var configErr *utils.ConfigError
+				assertions.ErrorAs(t, err, &configErr)
+				return
+			}
+			assertions.NoError(t, err)
+			assertions.Equal(t, tt.want, s.getSemver())
+		})
+	}
+}
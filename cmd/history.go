@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history [flags]",
+	Short: "Prints the version timeline of the repository",
+	Long: `Replays the semantic version calculation over the entire history and prints one row per commit changing the version:
+	version, commit, date, author, bump and the keyword triggering it, as a table, CSV or JSON (--format).
+	Useful to find which commit became a version in repositories that were never tagged, to retro-tag or audit.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.history(); err != nil {
+			utils.Critical("Unable to print the version history", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// history prints the commits at which the calculated version changes
+func (s *Setup) history() error {
+	changes, err := s.versionHistory()
+	if err != nil {
+		return err
+	}
+	output, err := utils.RenderHistory(changes, params.varHistoryFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// versionHistory calculates the version and replays the calculation over the same commits and tags
+func (s *Setup) versionHistory() ([]utils.VersionChange, error) {
+	if err := s.calculate(); err != nil {
+		return nil, err
+	}
+
+	var initial utils.SemVer
+	utils.ApplyForcedVersioning(s.Config.Force, &initial)
+	return utils.VersionHistory(
+		s.GitRepo.Commits,
+		s.GitRepo.Tags,
+		s.Config.Wording,
+		s.Config.Blacklist,
+		s.rulesAt(),
+		initial,
+		s.respectExisting(),
+		params.varStrict || s.Config.Force.Strict,
+		s.Config.TagPrefixes,
+	), nil
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&params.varHistoryFormat, "format", "f", utils.HistoryTable, "Output format: table, csv or json")
+	rootCmd.AddCommand(historyCmd)
+}
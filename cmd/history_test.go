@@ -0,0 +1,116 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_history(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	dir := initTestRepository(t, "Initial commit", "fix: typo", "Update docs", "feat: login").dir
+	configFile := filepath.Join(dir, "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+force:
+  strict: true
+wording:
+  patch:
+    - fix
+  minor:
+    - feat
+`), 0o600))
+	assertions.NoError(t, os.Chdir(dir))
+
+	params = myParams{varExisting: true, varHistoryFormat: utils.HistoryTable}
+	s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+	changes, err := s.versionHistory()
+	assertions.NoError(t, err)
+	if assertions.Len(t, changes, 2) {
+		assertions.Equal(t, "0.0.1", utils.FormatSemver(changes[0].Version))
+		assertions.Equal(t, "fix: typo", changes[0].Subject)
+		assertions.Equal(t, s.getSemver(), utils.FormatSemver(changes[1].Version), "the timeline ends at the calculated version")
+	}
+	assertions.NoError(t, s.history())
+
+	params.varHistoryFormat = "xml"
+	assertions.Error(t, s.history())
+}
+
+func TestSetup_historyConfig(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	// "Update" was a patch keyword until the repository switched to Conventional Commits
+	configOf := func(patch string) map[string]string {
+		return map[string]string{"semver.yaml": "version: 1\nforce:\n  strict: true\nwording:\n  patch:\n    - " + patch + "\n"}
+	}
+	repo := initTestRepository(t)
+	repo.commit("Initial commit", configOf("Update"))
+	repo.commit("Update docs", nil)
+	repo.commit("fix: typo", configOf("fix"))
+	assertions.NoError(t, os.Chdir(repo.dir))
+
+	tests := []struct {
+		name          string
+		historyConfig bool
+		want          []string
+		wantBumps     []utils.Bump
+	}{
+		{name: "Current configuration", want: []string{"0.0.1"}, wantBumps: []utils.Bump{utils.BumpNone, utils.BumpPatch}},
+		{name: "Configuration of each commit", historyConfig: true, want: []string{"0.0.1", "0.0.2"}, wantBumps: []utils.Bump{utils.BumpPatch, utils.BumpPatch}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params = myParams{varExisting: true}
+			s := &Setup{UseLocal: true, LocalConfigFile: "semver.yaml", HistoryConfig: tt.historyConfig}
+			changes, err := s.versionHistory()
+			assertions.NoError(t, err)
+			var versions []string
+			for _, change := range changes {
+				versions = append(versions, utils.FormatSemver(change.Version))
+			}
+			assertions.Equal(t, tt.want, versions)
+			assertions.Equal(t, tt.want[len(tt.want)-1], s.getSemver())
+
+			report, err := s.rangeReport(repo.hashes[0].String(), "HEAD")
+			assertions.NoError(t, err)
+			assertions.Equal(t, tt.wantBumps, report.Bumps)
+
+			// "Update docs" matches no level of the current strict configuration
+			params = myParams{varLintRange: repo.hashes[0].String() + ".."}
+			if tt.historyConfig {
+				assertions.NoError(t, s.lint(nil))
+			} else {
+				assertions.Error(t, s.lint(nil))
+			}
+		})
+	}
+}
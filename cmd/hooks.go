@@ -0,0 +1,149 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// hooksCmd represents the hooks command
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manages git hooks checking commits and pushed tags",
+	Long: `Installs or removes the commit-msg and pre-push hooks of the local repository, respecting core.hooksPath.
+	The commit-msg hook lints the message, the pre-push hook warns when a pushed tag disagrees with the calculated version.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+}
+
+// hooksInstallCmd represents the hooks install command
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install [flags]",
+	Short: "Installs the commit-msg and pre-push hooks",
+	Long: `Writes the commit-msg and pre-push hooks into the hooks directory of the local repository.
+	Existing hooks not installed by semver-generator are kept unless --force is set.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.installHooks(); err != nil {
+			utils.Critical("Unable to install hooks", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// hooksUninstallCmd represents the hooks uninstall command
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall [flags]",
+	Short: "Removes the commit-msg and pre-push hooks",
+	Long: `Removes the commit-msg and pre-push hooks installed by semver-generator, leaving any other hooks in place.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.uninstallHooks(); err != nil {
+			utils.Critical("Unable to uninstall hooks", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// hooksDir opens the local repository and returns its hooks directory
+func (s *Setup) hooksDir() (string, error) {
+	// Hooks only make sense for the repository in the current directory
+	s.GitRepo = utils.GitRepository{UseLocal: true}
+	if err := utils.PrepareRepository(&s.GitRepo); err != nil {
+		return "", err
+	}
+	return utils.HooksDir(&s.GitRepo)
+}
+
+// installHooks writes the managed hooks into the hooks directory
+func (s *Setup) installHooks() error {
+	dir, err := s.hooksDir()
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "semver-generator"
+	}
+	// Git runs the hooks from the root of the working tree, not from the directory they were installed in
+	configFile, err := filepath.Abs(s.LocalConfigFile)
+	if err != nil {
+		return err
+	}
+	scripts := utils.HookScripts(utils.HookOptions{
+		Executable: executable,
+		ConfigFile: configFile,
+		Strict:     params.varStrict,
+	})
+	for _, name := range hookNames(scripts) {
+		if err := utils.InstallHook(dir, name, scripts[name], params.varForce); err != nil {
+			return err
+		}
+		fmt.Println("HOOK", name, "installed in", dir)
+	}
+	return nil
+}
+
+// uninstallHooks removes the managed hooks from the hooks directory
+func (s *Setup) uninstallHooks() error {
+	dir, err := s.hooksDir()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range hookNames(utils.HookScripts(utils.HookOptions{})) {
+		removed, err := utils.UninstallHook(dir, name)
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Println("HOOK", name, "removed from", dir)
+		} else {
+			fmt.Println("HOOK", name, "not installed in", dir)
+		}
+	}
+	return nil
+}
+
+// hookNames returns the hook names in a stable order
+func hookNames(scripts map[string]string) []string {
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	hooksInstallCmd.Flags().BoolVar(&params.varForce, "force", false, "Overwrite existing hooks not installed by semver-generator")
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
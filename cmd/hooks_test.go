@@ -0,0 +1,64 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_hooks(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	dir := initTestRepository(t).dir
+	assertions.NoError(t, os.Chdir(dir))
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	configFile, err := filepath.Abs("semver.yaml")
+	assertions.NoError(t, err)
+
+	// The hooks do not depend on the directory git runs them from
+	params = myParams{}
+	s := &Setup{LocalConfigFile: "semver.yaml"}
+	assertions.NoError(t, s.installHooks())
+	for _, name := range []string{utils.HookCommitMsg, utils.HookPrePush} {
+		content, err := os.ReadFile(filepath.Join(hooksDir, name))
+		assertions.NoError(t, err, name)
+		assertions.Contains(t, string(content), utils.HookMarker, name)
+		assertions.Contains(t, string(content), "-c '"+configFile+"'", name)
+	}
+
+	// A foreign hook blocks the installation unless forced
+	assertions.NoError(t, os.WriteFile(filepath.Join(hooksDir, utils.HookPrePush), []byte("#!/bin/sh\n"), 0o600))
+	assertions.ErrorIs(t, s.installHooks(), utils.ErrForeignHook)
+	params.varForce = true
+	assertions.NoError(t, s.installHooks())
+
+	assertions.NoError(t, s.uninstallHooks())
+	assertions.NoFileExists(t, filepath.Join(hooksDir, utils.HookCommitMsg))
+	assertions.NoFileExists(t, filepath.Join(hooksDir, utils.HookPrePush))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [message-file...] [flags]",
+	Short: "Reports which version level commit messages trigger",
+	Long: `Checks commit messages against the configured wording and blacklist, reporting the level each one triggers.
+	Messages are read from files (e.g. as a commit-msg hook: semver-generator lint "$1", "-" for stdin),
+	from --message or from the commits of a --range. In strict mode every message has to match a level.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.lint(args); err != nil {
+			utils.Critical("Commit message lint failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// lint reports the level triggered by each message, failing in strict mode when one matches no level
+func (s *Setup) lint(files []string) error {
+	var results []utils.LintResult
+	if params.varLintRange != "" {
+		// As with git, a range without an end ends at HEAD
+		from, to, found := strings.Cut(params.varLintRange, "..")
+		if from == "" {
+			return fmt.Errorf("range %q has no start, e.g. v1.0.0..HEAD", params.varLintRange)
+		}
+		if !found || to == "" {
+			to = "HEAD"
+		}
+		if err := s.prepare(); err != nil {
+			return err
+		}
+		commits, err := utils.CommitRange(&s.GitRepo, from, to)
+		if err != nil {
+			return err
+		}
+		rulesAt := s.rulesAt()
+		for _, commit := range commits {
+			wording, blacklist := rulesAt.Of(commit.Hash, s.Config.Wording, s.Config.Blacklist)
+			result := utils.LintMessage(commit.Message, wording, blacklist)
+			result.Hash = commit.Hash
+			results = append(results, result)
+		}
+	} else {
+		if err := s.readConfig(); err != nil {
+			return err
+		}
+		messages, err := lintMessages(files)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			results = append(results, utils.LintMessage(message, s.Config.Wording, s.Config.Blacklist))
+		}
+	}
+
+	strict := params.varStrict || s.Config.Force.Strict
+	failed := 0
+	for _, result := range results {
+		printLintResult(result, strict)
+		if result.Failed(strict) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d commit messages do not match any configured level", failed, len(results))
+	}
+	return nil
+}
+
+// lintMessages reads the messages from the files, "-" being stdin, followed by the --message flags
+func lintMessages(files []string) ([]string, error) {
+	var messages []string
+	for _, file := range files {
+		var content []byte
+		var err error
+		if file == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			// #nosec G304 -- path of the commit message passed by git or the user
+			content, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read commit message: %w", err)
+		}
+		messages = append(messages, utils.CleanCommitMessage(string(content)))
+	}
+	messages = append(messages, params.varLintMessages...)
+
+	if len(messages) == 0 {
+		return nil, errors.New("nothing to lint, pass a message file, --message or --range")
+	}
+	return messages, nil
+}
+
+// printLintResult prints the level of the message with the keywords or blacklisted terms behind it
+func printLintResult(result utils.LintResult, strict bool) {
+	prefix := "LINT"
+	if result.Hash != "" {
+		prefix += " " + result.Hash[:7]
+	}
+	fmt.Printf("%s %-7s %s (%s)\n", prefix, result.Bump, result.Subject, lintNote(result, strict))
+}
+
+// lintNote explains the level of the message
+func lintNote(result utils.LintResult, strict bool) string {
+	switch {
+	case len(result.Blacklisted) > 0:
+		return "ignored, blacklisted: " + strings.Join(result.Blacklisted, ", ")
+	case len(result.Keywords) > 0:
+		return "matched: " + strings.Join(result.Keywords, ", ")
+	case strict:
+		return "does not match any configured level"
+	default:
+		return "no keyword, counted as patch outside of strict mode"
+	}
+}
+
+func init() {
+	lintCmd.Flags().StringArrayVar(&params.varLintMessages, "message", nil, "Commit message to lint, can be repeated")
+	lintCmd.Flags().StringVar(&params.varLintRange, "range", "", "Lint the commits of a range, e.g. v1.0.0..HEAD or origin/main..")
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_lint(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+wording:
+  patch: [fix]
+  minor: [feat]
+blacklist: ["Merge branch"]
+`), 0o600))
+	messageFile := filepath.Join(dir, "COMMIT_EDITMSG")
+	assertions.NoError(t, os.WriteFile(messageFile, []byte("fix: typo\n# Please enter the commit message\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		files   []string
+		params  myParams
+		wantErr bool
+	}{
+		{name: "Message file", files: []string{messageFile}},
+		{name: "Message flag", params: myParams{varLintMessages: []string{"feat: login", "Merge branch 'main'"}}},
+		{name: "Unmatched message", params: myParams{varLintMessages: []string{"Update readme"}}},
+		{name: "Unmatched message in strict mode", params: myParams{varStrict: true, varLintMessages: []string{"fix: typo", "Update readme"}}, wantErr: true},
+		{name: "Nothing to lint", wantErr: true},
+		{name: "Missing file", files: []string{filepath.Join(dir, "missing")}, wantErr: true},
+		{name: "Range without a start", params: myParams{varLintRange: "..HEAD"}, wantErr: true},
+		{name: "Range without either side", params: myParams{varLintRange: ".."}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params = tt.params
+			s := &Setup{LocalConfigFile: configFile}
+			err := s.lint(tt.files)
+			if tt.wantErr {
+				assertions.Error(t, err)
+			} else {
+				assertions.NoError(t, err)
+			}
+		})
+	}
+}
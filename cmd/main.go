@@ -18,8 +18,11 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/lukaszraczylo/semver-generator/cmd/utils"
@@ -34,12 +37,20 @@ var (
 type Setup struct {
 	RepositoryName   string
 	RepositoryBranch string
+	BranchSet        bool // Branch given explicitly, takes precedence over the CI environment
 	LocalConfigFile  string
+	ConfigSet        bool // Config file given explicitly rather than discovered
+	RemoteConfig     bool // Read the configuration of a remote repository from the clone, the local file being the fallback
+	HistoryConfig    bool // Classify every commit with the configuration file as it is at that commit
 	Generate         bool
 	UseLocal         bool
 	GitRepo          utils.GitRepository
 	Config           *utils.Config
 	Semver           utils.SemVer
+	CI               utils.CIEnvironment
+	IgnoreHeadTags   bool                  // Tags on the calculated commit are not used as the baseline, e.g. when verifying them
+	Hypothetical     []utils.CommitDetails // Commits appended to the history, e.g. to preview the next version
+	Ref              string                // Revision to calculate the version at, HEAD when empty
 }
 
 // Initialize the fuzzy search function in the utils package
@@ -93,61 +104,338 @@ func main() {
 
 	// Generate semantic version
 	if repo.Generate || params.varGenerateInTest {
-		// Read configuration
-		config, err := utils.ReadConfig(repo.LocalConfigFile)
-		if err != nil {
-			utils.Error("Unable to find config file. Using defaults and flags.", map[string]interface{}{
-				"file": repo.LocalConfigFile,
+		if err := repo.calculate(); err != nil {
+			message := "Unable to prepare repository"
+			var configErr *utils.ConfigError
+			if errors.As(err, &configErr) {
+				message = "Configuration is not valid"
+			}
+			utils.Critical(message, map[string]interface{}{
+				"error": err.Error(),
 			})
+			os.Exit(1)
 		}
-		repo.Config = config
 
-		// Setup git repository
-		gitRepo := utils.GitRepository{
-			Name:        repo.RepositoryName,
-			Branch:      repo.RepositoryBranch,
-			UseLocal:    repo.UseLocal,
-			StartCommit: repo.Config.Force.Commit,
+		// Print semantic version, or write it as environment variables when requested
+		if params.varOutput != "" || params.varOutputFile != "" {
+			if err := repo.writeEnvOutput(); err != nil {
+				utils.Critical("Unable to write output", map[string]interface{}{
+					"error": err.Error(),
+				})
+				os.Exit(1)
+			}
+		} else {
+			fmt.Println("SEMVER", repo.getSemver())
 		}
-		repo.GitRepo = gitRepo
 
-		// Prepare repository
-		err = utils.PrepareRepository(&repo.GitRepo)
-		if err != nil {
-			utils.Critical("Unable to prepare repository", map[string]interface{}{
-				"error": err.Error(),
+		if utils.IsGitHubActions() {
+			repo.publishActions()
+		}
+	}
+}
+
+// respectExisting reports whether existing tags are used as the baseline
+func (s *Setup) respectExisting() bool {
+	return params.varExisting || s.Config.Force.Existing
+}
+
+// calculate reads the configuration, prepares the repository and calculates the semantic version
+func (s *Setup) calculate() error {
+	if err := s.prepare(); err != nil {
+		return err
+	}
+	s.compute()
+	return nil
+}
+
+// configSources returns the configuration file, the environment and the --set overrides
+func (s *Setup) configSources() utils.ConfigSources {
+	return utils.ConfigSources{
+		File:       s.LocalConfigFile,
+		Repository: s.repositoryRoot(),
+		Env:        os.Environ(),
+		Overrides:  params.varSet,
+	}
+}
+
+// repositoryRoot returns the directory of the repository being versioned, for configurations extending its files.
+// The local repository is the current directory, and so is a remote one once cloned.
+func (s *Setup) repositoryRoot() string {
+	if !s.UseLocal && s.GitRepo.Handler == nil {
+		return ""
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// readConfig reads the configuration, falling back to defaults when the file is missing.
+// Errors of an existing file are returned as a *utils.ConfigError, its warnings are logged.
+// The environment and --set overrides are applied on top of it.
+func (s *Setup) readConfig() error {
+	sources := s.configSources()
+	issues, err := utils.ValidateConfig(s.LocalConfigFile)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		utils.Error("Unable to find config file. Using defaults and flags.", map[string]interface{}{
+			"file": s.LocalConfigFile,
+		})
+		sources.File = ""
+	case errors.Is(err, utils.ErrUnsupportedConfigFormat):
+		utils.Debug("Skipping config validation", map[string]interface{}{
+			"file": s.LocalConfigFile,
+		})
+	case err != nil:
+		return err
+	}
+	if err := checkConfigIssues(issues); err != nil {
+		return err
+	}
+
+	config, err := utils.LoadConfig(sources)
+	if err != nil {
+		return err
+	}
+	s.Config = config
+	return nil
+}
+
+// checkConfigIssues logs the warnings among the issues of the configuration and returns its errors as a *utils.ConfigError
+func checkConfigIssues(issues []utils.ConfigIssue) error {
+	errs, warnings := utils.SplitConfigIssues(issues)
+	for _, warning := range warnings {
+		utils.Info("Configuration warning", map[string]interface{}{
+			"issue": warning.String(),
+		})
+	}
+	if len(errs) > 0 {
+		return &utils.ConfigError{Issues: errs}
+	}
+	return nil
+}
+
+// prepare reads the configuration and prepares the repository
+func (s *Setup) prepare() error {
+	// Setup git repository
+	s.GitRepo = utils.GitRepository{
+		Name:     s.RepositoryName,
+		Branch:   s.RepositoryBranch,
+		UseLocal: s.UseLocal,
+	}
+	s.applyCI()
+
+	// The configuration of a remote repository can only be read once it is cloned,
+	// and so can a local configuration extending one of its files
+	remoteConfig := s.RemoteConfig && !s.UseLocal
+	deferConfig := remoteConfig
+	var localConfigFile string
+	if !remoteConfig {
+		err := s.readConfig()
+		switch {
+		case errors.Is(err, utils.ErrRepositoryUnavailable):
+			utils.Debug("Configuration extends a file of the repository, reading it once cloned", map[string]interface{}{
+				"file": s.LocalConfigFile,
 			})
-			os.Exit(1)
+			deferConfig = true
+		case err != nil:
+			return err
 		}
+	}
+	if deferConfig {
+		// The clone becomes the current directory, the local file has to be found from the current one
+		var err error
+		if localConfigFile, err = filepath.Abs(s.LocalConfigFile); err != nil {
+			return err
+		}
+	}
 
-		// List commits
-		if _, err := utils.ListCommits(&repo.GitRepo); err != nil {
-			utils.Error("Unable to list commits", map[string]interface{}{
-				"error": err.Error(),
+	// Prepare repository
+	if err := utils.PrepareRepository(&s.GitRepo); err != nil {
+		return err
+	}
+
+	// An explicit revision takes precedence over the CI commit
+	if s.Ref != "" {
+		commit, err := utils.ResolveRevision(&s.GitRepo, s.Ref)
+		if err != nil {
+			return err
+		}
+		s.GitRepo.Commit = commit
+	}
+
+	switch {
+	case remoteConfig:
+		if err := s.readRemoteConfig(localConfigFile); err != nil {
+			return err
+		}
+	case deferConfig:
+		s.LocalConfigFile = localConfigFile
+		if err := s.readConfig(); err != nil {
+			return err
+		}
+	}
+	s.GitRepo.StartCommit = s.Config.Force.Commit
+
+	// Only trust signed tags when required
+	if s.Config.Signing.RequireSignedTags {
+		verifier, err := utils.NewTagVerifier(s.Config.Signing)
+		if err != nil {
+			return err
+		}
+		s.GitRepo.TagVerifier = verifier
+	}
+	return nil
+}
+
+// repositoryConfigFiles returns the paths the configuration is looked for in the tree of the repository:
+// the --config path when given relative to its root, the usual names otherwise
+func (s *Setup) repositoryConfigFiles() []string {
+	if s.ConfigSet && filepath.IsLocal(s.LocalConfigFile) {
+		return []string{s.LocalConfigFile}
+	}
+	return utils.ConfigFileNames
+}
+
+// readRemoteConfig reads the configuration of the cloned repository as it is at the calculated commit,
+// falling back to the local file when the repository has none
+func (s *Setup) readRemoteConfig(localConfigFile string) error {
+	read, err := utils.CommitFileReader(&s.GitRepo, "")
+	if err != nil {
+		return err
+	}
+	for _, file := range s.repositoryConfigFiles() {
+		if _, err := read(file); errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		issues, err := utils.ValidateConfigFrom(file, read)
+		switch {
+		case errors.Is(err, utils.ErrUnsupportedConfigFormat):
+			utils.Debug("Skipping config validation", map[string]interface{}{
+				"file": file,
 			})
+		case err != nil:
+			return err
+		}
+		if err := checkConfigIssues(issues); err != nil {
+			return err
 		}
 
-		// List existing tags if needed
-		if params.varExisting || repo.Config.Force.Existing {
-			utils.ListExistingTags(&repo.GitRepo, repo.Config.TagPrefixes)
+		utils.Debug("Using the configuration of the repository", map[string]interface{}{
+			"file":   file,
+			"commit": s.GitRepo.Commit,
+		})
+		sources := s.configSources()
+		sources.File, sources.ReadFile, sources.Repository = file, read, "."
+		config, err := utils.LoadConfig(sources)
+		if err != nil {
+			return err
 		}
+		s.Config = config
+		return nil
+	}
 
-		// Apply forced versioning
-		utils.ApplyForcedVersioning(repo.Config.Force, &repo.Semver)
+	utils.Info("No configuration in the repository, using the local file", map[string]interface{}{
+		"file": localConfigFile,
+	})
+	s.LocalConfigFile = localConfigFile
+	return s.readConfig()
+}
 
-		// Calculate semantic version
-		repo.Semver = utils.CalculateSemver(
-			repo.GitRepo.Commits,
-			repo.GitRepo.Tags,
-			repo.Config.Wording,
-			repo.Config.Blacklist,
-			repo.Semver,
-			params.varExisting || repo.Config.Force.Existing,
-			params.varStrict || repo.Config.Force.Strict,
-			repo.Config.TagPrefixes,
-		)
+// applyCI feeds the branch and commit of the CI build into the repository.
+// A remote repository only uses them when it is the repository being built.
+func (s *Setup) applyCI() {
+	s.CI = utils.DetectCI()
+	if s.UseLocal && !s.BranchSet {
+		// The default branch must not override a local checkout
+		s.GitRepo.Branch = ""
+	}
+	if !s.CI.Detected() {
+		return
+	}
+	utils.Debug("Detected CI environment", map[string]interface{}{
+		"provider":     s.CI.Provider,
+		"branch":       s.CI.Branch,
+		"pull_request": s.CI.PullRequest,
+		"commit":       s.CI.Commit,
+	})
+	if !s.UseLocal && !s.CI.Builds(s.RepositoryName) {
+		return
+	}
+	if !s.BranchSet && s.CI.Branch != "" {
+		s.GitRepo.Branch = s.CI.Branch
+	}
+	s.GitRepo.Commit = s.CI.Commit
+}
+
+// compute calculates the semantic version of the prepared repository.
+// It can be called repeatedly, e.g. after fetching tags created in the meantime.
+func (s *Setup) compute() {
+	s.GitRepo.Tags = nil
+	s.GitRepo.SkippedTags = nil
+	s.Semver = utils.SemVer{}
+
+	// List commits
+	if _, err := utils.ListCommits(&s.GitRepo); err != nil {
+		utils.Error("Unable to list commits", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	s.GitRepo.Commits = append(s.GitRepo.Commits, s.Hypothetical...)
+
+	// List existing tags if needed
+	if s.respectExisting() {
+		s.listExistingTags()
+	}
+
+	// Apply forced versioning
+	utils.ApplyForcedVersioning(s.Config.Force, &s.Semver)
+
+	// Calculate semantic version
+	s.Semver = utils.CalculateSemver(
+		s.GitRepo.Commits,
+		s.GitRepo.Tags,
+		s.Config.Wording,
+		s.Config.Blacklist,
+		s.rulesAt(),
+		s.Semver,
+		s.respectExisting(),
+		params.varStrict || s.Config.Force.Strict,
+		s.Config.TagPrefixes,
+	)
+}
 
-		// Print semantic version
-		fmt.Println("SEMVER", repo.getSemver())
+// rulesAt returns the rules of each commit when commits are classified with the configuration of their time,
+// nil to classify them all with the current configuration
+func (s *Setup) rulesAt() utils.RulesAt {
+	if !s.HistoryConfig {
+		return nil
+	}
+	return utils.HistoricalRules(&s.GitRepo, s.repositoryConfigFiles(), s.configSources(), utils.CommitRules{
+		Wording:   s.Config.Wording,
+		Blacklist: s.Config.Blacklist,
+	})
+}
+
+// listExistingTags lists the tags of the repository, leaving out the ones on the calculated commit when IgnoreHeadTags is set
+func (s *Setup) listExistingTags() {
+	s.GitRepo.Tags = nil
+	s.GitRepo.SkippedTags = nil
+	utils.ListExistingTags(&s.GitRepo, s.Config.TagPrefixes)
+	if !s.IgnoreHeadTags || len(s.GitRepo.Commits) == 0 {
+		return
+	}
+
+	head := s.GitRepo.Commits[len(s.GitRepo.Commits)-1].Hash
+	tags := s.GitRepo.Tags[:0]
+	for _, tag := range s.GitRepo.Tags {
+		if tag.Hash != head {
+			tags = append(tags, tag)
+		}
 	}
+	s.GitRepo.Tags = tags
 }
@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/lukaszraczylo/pandati"
 	"github.com/lukaszraczylo/semver-generator/cmd/utils"
 	assertions "github.com/stretchr/testify/assert"
@@ -36,6 +41,97 @@ func TestSuite(t *testing.T) {
 	suite.Run(t, new(Tests))
 }
 
+// testRepository is a temporary git repository created by initTestRepository
+type testRepository struct {
+	t        *testing.T
+	dir      string
+	handler  *git.Repository
+	worktree *git.Worktree
+	hashes   []plumbing.Hash
+	when     time.Time
+}
+
+// initTestRepository creates a temporary git repository on the main branch with one commit per message,
+// an hour apart from 2021-01-01, and Test Author as the configured git user
+func initTestRepository(t *testing.T, messages ...string) *testRepository {
+	t.Helper()
+
+	dir := t.TempDir()
+	handler, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+
+	cfg, err := handler.Config()
+	if err != nil {
+		t.Fatalf("Failed to read repository config: %v", err)
+	}
+	cfg.User.Name = "Test Author"
+	cfg.User.Email = "test@example.com"
+	if err := handler.SetConfig(cfg); err != nil {
+		t.Fatalf("Failed to write repository config: %v", err)
+	}
+
+	worktree, err := handler.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	repo := &testRepository{
+		t:        t,
+		dir:      dir,
+		handler:  handler,
+		worktree: worktree,
+		when:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, message := range messages {
+		repo.commit(message, nil)
+	}
+	return repo
+}
+
+// signature returns the identity of the test commits and tags, an hour after the previous one
+func (r *testRepository) signature() *object.Signature {
+	r.when = r.when.Add(time.Hour)
+	return &object.Signature{Name: "Test Author", Email: "test@example.com", When: r.when}
+}
+
+// commit writes the message to file.txt, along with the files (path relative to the repository, content),
+// and commits them
+func (r *testRepository) commit(message string, files map[string]string) plumbing.Hash {
+	r.t.Helper()
+
+	contents := map[string]string{"file.txt": message}
+	for path, content := range files {
+		contents[path] = content
+	}
+	for path, content := range contents {
+		if err := os.WriteFile(filepath.Join(r.dir, path), []byte(content), 0o600); err != nil {
+			r.t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := r.worktree.Add(path); err != nil {
+			r.t.Fatalf("Failed to stage file: %v", err)
+		}
+	}
+	hash, err := r.worktree.Commit(message, &git.CommitOptions{Author: r.signature()})
+	if err != nil {
+		r.t.Fatalf("Failed to commit: %v", err)
+	}
+	r.hashes = append(r.hashes, hash)
+	return hash
+}
+
+// tag creates a lightweight tag on the commit
+func (r *testRepository) tag(name string, hash plumbing.Hash) {
+	r.t.Helper()
+
+	if _, err := r.handler.CreateTag(name, hash, nil); err != nil {
+		r.t.Fatalf("Failed to tag: %v", err)
+	}
+}
+
 func (suite *Tests) TestSetup_getSemver() {
 	type fields struct {
 		Semver utils.SemVer
@@ -493,18 +589,7 @@ func (suite *Tests) TestSetup_ListCommits() {
 }
 
 func (suite *Tests) Test_main() {
-	type vars struct {
-		varRepoName       string
-		varRepoBranch     string
-		varLocalCfg       string
-		varUseLocal       bool
-		varShowVersion    bool
-		varDebug          bool
-		varUpdate         bool
-		varStrict         bool
-		varGenerateInTest bool
-		varExisting       bool
-	}
+	type vars = myParams
 	tests := []struct {
 		name string
 		vars vars
@@ -530,7 +615,7 @@ func (suite *Tests) Test_main() {
 	}
 	for _, tt := range tests {
 		suite.T().Run(tt.name, func(t *testing.T) {
-			params = myParams(tt.vars)
+			params = tt.vars
 			repo = &Setup{}
 			repo.LocalConfigFile = "../config.yaml"
 			repo.UseLocal = true
@@ -0,0 +1,112 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// nextCmd represents the next command
+var nextCmd = &cobra.Command{
+	Use:   "next [flags]",
+	Short: "Previews the semantic version hypothetical commits would produce",
+	Long: `Appends hypothetical commit messages to the history and calculates the resulting semantic version,
+	showing the level each message triggers. Messages are given with --message (repeatable) or read from stdin,
+	one per line (e.g. git log --reverse --format=%s main..feature | semver-generator next -l).
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.next(os.Stdin); err != nil {
+			utils.Critical("Unable to preview the next version", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// next calculates the semantic version with the hypothetical commits appended to the history
+func (s *Setup) next(stdin io.Reader) error {
+	messages := params.varNextMessages
+	if len(messages) == 0 {
+		var err error
+		if messages, err = readMessageLines(stdin); err != nil {
+			return err
+		}
+	}
+	if len(messages) == 0 {
+		return errors.New("no commit messages, pass --message or pipe them to stdin")
+	}
+
+	if err := s.prepare(); err != nil {
+		return err
+	}
+	s.Hypothetical = hypotheticalCommits(messages)
+	s.compute()
+
+	strict := params.varStrict || s.Config.Force.Strict
+	for _, message := range messages {
+		result := utils.LintMessage(message, s.Config.Wording, s.Config.Blacklist)
+		fmt.Printf("NEXT %-7s %s (%s)\n", result.Bump, result.Subject, lintNote(result, strict))
+	}
+	fmt.Println("SEMVER", s.getSemver())
+	return nil
+}
+
+// readMessageLines reads one commit message per non-empty line
+func readMessageLines(r io.Reader) ([]string, error) {
+	var messages []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			messages = append(messages, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read commit messages: %w", err)
+	}
+	return messages, nil
+}
+
+// hypotheticalCommits turns the messages into commits following the history, in the given order.
+// They have no hash, so no existing tag can point at them.
+func hypotheticalCommits(messages []string) []utils.CommitDetails {
+	now := time.Now()
+	commits := make([]utils.CommitDetails, 0, len(messages))
+	for i, message := range messages {
+		commits = append(commits, utils.CommitDetails{
+			Timestamp: now.Add(time.Duration(i) * time.Second),
+			Author:    "hypothetical",
+			Message:   message,
+		})
+	}
+	return commits
+}
+
+func init() {
+	nextCmd.Flags().StringArrayVar(&params.varNextMessages, "message", nil, "Hypothetical commit message, can be repeated")
+	rootCmd.AddCommand(nextCmd)
+}
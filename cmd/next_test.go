@@ -0,0 +1,83 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_next(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "Update docs")
+	repo.tag("v1.0.0", repo.hashes[0])
+	dir := repo.dir
+	configFile := filepath.Join(dir, "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+force:
+  strict: true
+wording:
+  patch:
+    - fix
+  minor:
+    - feat
+  major:
+    - breaking
+`), 0o600))
+	assertions.NoError(t, os.Chdir(dir))
+
+	tests := []struct {
+		name        string
+		messages    []string
+		stdin       string
+		wantVersion string
+		wantErr     bool
+	}{
+		{name: "Feature", messages: []string{"feat: login"}, wantVersion: "1.1.1"},
+		{name: "Fix after feature", messages: []string{"feat: login", "fix: typo"}, wantVersion: "1.1.2"},
+		{name: "Breaking change", messages: []string{"fix: typo", "breaking: drop v1 API"}, wantVersion: "2.0.1"},
+		{name: "Unmatched message in strict mode", messages: []string{"Update readme"}, wantVersion: "1.0.0"},
+		{name: "Messages from stdin", stdin: "feat: login\n\nfix: typo\n", wantVersion: "1.1.2"},
+		{name: "No messages", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params = myParams{varExisting: true, varNextMessages: tt.messages}
+			s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+			err := s.next(strings.NewReader(tt.stdin))
+			if tt.wantErr {
+				assertions.Error(t, err)
+				return
+			}
+			assertions.NoError(t, err)
+			assertions.Equal(t, tt.wantVersion, s.getSemver())
+			// Hypothetical commits never reach the listed history
+			commits, _ := utils.ListCommits(&s.GitRepo)
+			assertions.Len(t, commits, 2)
+		})
+	}
+}
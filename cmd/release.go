@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release [flags]",
+	Short: "Updates version files, commits them and tags the release",
+	Long: `Calculates the semantic version, writes it to the files listed in the configuration,
+	creates a release commit (default message "chore(release): v{{ .Version }}") and tags it.
+	When no file changes, the calculated commit is tagged without a commit. With --push the branch and the tag are pushed to the remote.
+	Pull request builds detected from the CI environment are always dry runs.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.release(); err != nil {
+			utils.Critical("Unable to create release", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// releaseMessageTemplate returns the release commit message template, the flag taking precedence over the config
+func (s *Setup) releaseMessageTemplate() string {
+	message := utils.DefaultReleaseMessage
+	if s.Config != nil && s.Config.Release.Message != "" {
+		message = s.Config.Release.Message
+	}
+	if params.varReleaseMessage != "" {
+		message = params.varReleaseMessage
+	}
+	return message
+}
+
+// release calculates the semantic version, commits the updated version files and tags the result
+func (s *Setup) release() error {
+	if err := s.calculate(); err != nil {
+		return err
+	}
+	s.dryRunPullRequest()
+
+	// The tag goes on the commit the version was calculated for, or on the release commit when one is created
+	target, err := utils.ResolveHead(&s.GitRepo)
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	if len(s.Config.Files) > 0 {
+		var err error
+		if changed, err = s.updateVersionFiles(); err != nil {
+			return err
+		}
+	}
+
+	if len(changed) > 0 {
+		message, err := utils.RenderVersionTemplate(s.releaseMessageTemplate(), s.Semver)
+		if err != nil {
+			return err
+		}
+		signer, err := utils.LoadSigner(s.Config.Signing)
+		if err != nil {
+			return err
+		}
+		author := utils.ReleaseSignature(&s.GitRepo, s.Config.Release.Author)
+		hash, err := utils.CreateCommit(&s.GitRepo, changed, message, author, signer, params.varDryRun)
+		if err != nil {
+			return err
+		}
+		if hash == "" {
+			fmt.Println("COMMIT", "(dry run)", message)
+		} else {
+			fmt.Println("COMMIT", hash, message)
+			target = hash
+		}
+	} else {
+		utils.Info("No version files changed, tagging the calculated commit", map[string]interface{}{
+			"commit": target,
+		})
+	}
+
+	name, err := s.createTag(target)
+	if err != nil {
+		return err
+	}
+
+	if params.varPush {
+		if len(changed) > 0 {
+			if err := utils.PushBranch(&s.GitRepo, params.varRemote, params.varDryRun); err != nil {
+				return err
+			}
+		}
+		if err := utils.PushTag(&s.GitRepo, params.varRemote, name, params.varDryRun); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("SEMVER", s.getSemver())
+	fmt.Println("TAG", name)
+	return nil
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&params.varReleaseMessage, "commit-message", "", "Release commit message template (default \""+utils.DefaultReleaseMessage+"\")")
+	releaseCmd.Flags().BoolVar(&params.varDryRun, "dry-run", false, "Show the release which would be created without changing the repository")
+	releaseCmd.Flags().BoolVar(&params.varPush, "push", false, "Push the release commit and tag to the remote")
+	releaseCmd.Flags().StringVar(&params.varRemote, "remote", "origin", "Remote to push the release to")
+	rootCmd.AddCommand(releaseCmd)
+}
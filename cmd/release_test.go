@@ -0,0 +1,104 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_release(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t)
+	repo.commit("Initial commit", map[string]string{"package.json": "{\n  \"version\": \"0.0.0\"\n}\n"})
+	dir, handler, worktree := repo.dir, repo.handler, repo.worktree
+
+	configFile := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte(`version: 1
+files:
+  - path: package.json
+release:
+  message: "release: {{ .Version }}"
+  author:
+    name: Release Bot
+    email: bot@example.com
+`), 0o600))
+
+	assertions.NoError(t, os.Chdir(dir))
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	params = myParams{varExisting: true}
+	s := &Setup{UseLocal: true, LocalConfigFile: configFile}
+	assertions.NoError(t, s.release())
+
+	version := s.getSemver()
+	content, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+	assertions.Equal(t, "{\n  \"version\": \""+version+"\"\n}\n", string(content))
+
+	head, _ := handler.Head()
+	commit, err := handler.CommitObject(head.Hash())
+	assertions.NoError(t, err)
+	assertions.Equal(t, "release: "+version, commit.Message)
+	assertions.Equal(t, "Release Bot", commit.Author.Name)
+
+	tag, err := handler.Tag("v" + version)
+	assertions.NoError(t, err)
+	tagObj, err := handler.TagObject(tag.Hash())
+	assertions.NoError(t, err)
+	assertions.Equal(t, head.Hash(), tagObj.Target, "Tag should point to the release commit")
+
+	status, _ := worktree.Status()
+	assertions.True(t, status.IsClean(), "Working tree should be clean after the release")
+}
+
+func TestSetup_releaseAtRef(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "fix: first", "fix: second")
+	dir, handler, hashes := repo.dir, repo.handler, repo.hashes
+	configFile := filepath.Join(t.TempDir(), "semver.yaml")
+	assertions.NoError(t, os.WriteFile(configFile, []byte("version: 1\nwording:\n  patch: [fix]\n"), 0o600))
+
+	assertions.NoError(t, os.Chdir(dir))
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	params = myParams{}
+	s := &Setup{UseLocal: true, LocalConfigFile: configFile, Ref: "HEAD~1"}
+	assertions.NoError(t, s.release())
+
+	tag, err := handler.Tag("v" + s.getSemver())
+	assertions.NoError(t, err)
+	tagObj, err := handler.TagObject(tag.Hash())
+	assertions.NoError(t, err)
+	assertions.Equal(t, hashes[1], tagObj.Target, "Tag should point to the calculated commit")
+	head, _ := handler.Head()
+	assertions.Equal(t, hashes[2], head.Hash(), "No release commit without version files")
+}
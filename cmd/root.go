@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -48,25 +49,62 @@ func (r *Setup) setupCobra() {
 	if err != nil {
 		panic(err)
 	}
+	r.BranchSet = rootCmd.PersistentFlags().Changed("branch")
 	r.LocalConfigFile, err = rootCmd.Flags().GetString("config")
 	if err != nil {
 		panic(err)
 	}
+	r.ConfigSet = rootCmd.PersistentFlags().Changed("config")
+	if !r.ConfigSet {
+		// Look for the configuration in the usual places and the parent directories
+		if found, err := utils.FindConfigFile("."); err == nil && found != "" {
+			r.LocalConfigFile = found
+		}
+	}
 	r.UseLocal = params.varUseLocal
+	r.RemoteConfig = params.varRemoteConfig
+	r.HistoryConfig = params.varHistoryConfig
 }
 
 // myParams holds the command line parameters
 type myParams struct {
-	varRepoName       string
-	varRepoBranch     string
-	varLocalCfg       string
-	varUseLocal       bool
-	varShowVersion    bool
-	varDebug          bool
-	varUpdate         bool
-	varStrict         bool
-	varGenerateInTest bool
-	varExisting       bool
+	varRepoName          string
+	varRepoBranch        string
+	varLocalCfg          string
+	varUseLocal          bool
+	varShowVersion       bool
+	varDebug             bool
+	varUpdate            bool
+	varStrict            bool
+	varGenerateInTest    bool
+	varExisting          bool
+	varDryRun            bool
+	varTagName           string
+	varTagMessage        string
+	varPush              bool
+	varRemote            string
+	varChangelogTemplate string
+	varChangelogFile     string
+	varOutput            string
+	varOutputFile        string
+	varReleaseMessage    string
+	varExpect            string
+	varVerifyTag         string
+	varLintMessages      []string
+	varLintRange         string
+	varForce             bool
+	varNextMessages      []string
+	varRef               string
+	varFrom              string
+	varTo                string
+	varHistoryFormat     string
+	varBackfillLevel     string
+	varApply             bool
+	varPreset            string
+	varInspect           bool
+	varSet               []string
+	varRemoteConfig      bool
+	varHistoryConfig     bool
 }
 
 var params myParams
@@ -76,8 +114,11 @@ func init() {
 	cobra.OnInitialize(repo.setupCobra)
 	rootCmd.PersistentFlags().StringVarP(&params.varRepoName, "repository", "r", "https://github.com/lukaszraczylo/simple-gql-client", "Remote repository URL.")
 	rootCmd.PersistentFlags().StringVarP(&params.varRepoBranch, "branch", "b", "main", "Remote repository URL Branch.")
-	rootCmd.PersistentFlags().StringVarP(&params.varLocalCfg, "config", "c", "semver.yaml", "Path to config file")
+	rootCmd.PersistentFlags().StringVarP(&params.varLocalCfg, "config", "c", "semver.yaml", "Path to config file, looked for in the current and parent directories when not given")
+	rootCmd.PersistentFlags().StringArrayVar(&params.varSet, "set", nil, "Override a configuration key, e.g. --set wording.major=breaking (repeatable)")
 	rootCmd.PersistentFlags().BoolVarP(&params.varUseLocal, "local", "l", false, "Use local repository")
+	rootCmd.PersistentFlags().BoolVar(&params.varRemoteConfig, "remote-config", false, "Read the configuration from the remote repository at the calculated commit, falling back to the local file")
+	rootCmd.PersistentFlags().BoolVar(&params.varHistoryConfig, "history-config", false, "Classify each commit with the configuration file as it is at that commit, keeping historical versions stable")
 	rootCmd.PersistentFlags().BoolVarP(&params.varShowVersion, "version", "v", false, "Display version")
 	rootCmd.PersistentFlags().BoolVarP(&params.varDebug, "debug", "d", false, "Enable debug mode")
 	rootCmd.PersistentFlags().BoolVarP(&params.varUpdate, "update", "u", false, "Update binary with latest")
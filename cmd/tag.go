@@ -0,0 +1,159 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag [flags]",
+	Short: "Creates a git tag with the generated semantic version",
+	Long: `Calculates the semantic version and creates an annotated tag on HEAD of the repository.
+	Tag name and message are templates with access to {{ .Version }}, {{ .Major }}, {{ .Minor }}, {{ .Patch }} and {{ .Release }}.
+	Existing tags are never overwritten. With --push the tag is pushed to the remote, and if another
+	pipeline pushed the same tag in the meantime the version is recalculated with the remote tags.
+	Pull request builds detected from the CI environment are always dry runs.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.tag(); err != nil {
+			utils.Critical("Unable to create tag", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// maxPushAttempts limits how many times the version is recalculated when the tag already exists on the remote
+const maxPushAttempts = 3
+
+// tagTemplates returns the tag name and message templates, flags taking precedence over the config
+func (s *Setup) tagTemplates() (string, string) {
+	name, message := utils.DefaultTagName, utils.DefaultTagMessage
+	if s.Config != nil && s.Config.Tag.Name != "" {
+		name = s.Config.Tag.Name
+	}
+	if s.Config != nil && s.Config.Tag.Message != "" {
+		message = s.Config.Tag.Message
+	}
+	if params.varTagName != "" {
+		name = params.varTagName
+	}
+	if params.varTagMessage != "" {
+		message = params.varTagMessage
+	}
+	return name, message
+}
+
+// tag calculates the semantic version and creates the tag for it
+func (s *Setup) tag() error {
+	if err := s.prepare(); err != nil {
+		return err
+	}
+	s.dryRunPullRequest()
+
+	var name string
+	for attempt := 1; ; attempt++ {
+		s.compute()
+
+		// The version belongs to the calculated commit, e.g. the CI commit or --ref, which may not be HEAD
+		commit, err := utils.ResolveHead(&s.GitRepo)
+		if err != nil {
+			return err
+		}
+		name, err = s.createTag(commit)
+		if err != nil {
+			return err
+		}
+		if !params.varPush {
+			break
+		}
+
+		err = utils.PushTag(&s.GitRepo, params.varRemote, name, params.varDryRun)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, utils.ErrTagExistsOnRemote) || params.varDryRun || attempt >= maxPushAttempts {
+			return err
+		}
+
+		utils.Info("Tag already exists on remote, recalculating", map[string]interface{}{
+			"tag":     name,
+			"remote":  params.varRemote,
+			"attempt": attempt,
+		})
+		if err := utils.DeleteTag(&s.GitRepo, name); err != nil {
+			return err
+		}
+		if err := utils.FetchTags(&s.GitRepo, params.varRemote); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("SEMVER", s.getSemver())
+	fmt.Println("TAG", name)
+	return nil
+}
+
+// dryRunPullRequest turns tagging into a dry run on pull request builds of the repository,
+// as the commit being built is not on the branch yet
+func (s *Setup) dryRunPullRequest() {
+	if s.CI.PullRequest == "" || params.varDryRun || (!s.UseLocal && !s.CI.Builds(s.RepositoryName)) {
+		return
+	}
+	utils.Info("Pull request build, showing the release without changing the repository", map[string]interface{}{
+		"pull_request": s.CI.PullRequest,
+	})
+	params.varDryRun = true
+}
+
+// createTag renders the tag templates for the calculated version and creates the tag on the commit, HEAD when empty
+func (s *Setup) createTag(commit string) (string, error) {
+	nameTemplate, messageTemplate := s.tagTemplates()
+	name, err := utils.RenderVersionTemplate(nameTemplate, s.Semver)
+	if err != nil {
+		return "", err
+	}
+	message, err := utils.RenderVersionTemplate(messageTemplate, s.Semver)
+	if err != nil {
+		return "", err
+	}
+	signer, err := utils.LoadSigner(s.Config.Signing)
+	if err != nil {
+		return "", err
+	}
+	if commit == "" {
+		return name, utils.CreateTag(&s.GitRepo, name, message, signer, params.varDryRun)
+	}
+	return name, utils.CreateTagAt(&s.GitRepo, name, commit, message, signer, params.varDryRun)
+}
+
+func init() {
+	tagCmd.Flags().StringVar(&params.varTagName, "name", "", "Tag name template (default \""+utils.DefaultTagName+"\")")
+	tagCmd.Flags().StringVar(&params.varTagMessage, "message", "", "Tag message template (default \""+utils.DefaultTagMessage+"\")")
+	tagCmd.Flags().BoolVar(&params.varDryRun, "dry-run", false, "Show the tag which would be created without creating it")
+	tagCmd.Flags().BoolVar(&params.varPush, "push", false, "Push the created tag to the remote")
+	tagCmd.Flags().StringVar(&params.varRemote, "remote", "origin", "Remote to push the tag to")
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_tagTemplates(t *testing.T) {
+	originalParams := params
+	defer func() { params = originalParams }()
+
+	tests := []struct {
+		name        string
+		config      *utils.Config
+		params      myParams
+		wantName    string
+		wantMessage string
+	}{
+		{
+			name:        "Defaults without config",
+			wantName:    utils.DefaultTagName,
+			wantMessage: utils.DefaultTagMessage,
+		},
+		{
+			name:        "Config templates",
+			config:      &utils.Config{Tag: utils.Tag{Name: "app-{{ .Version }}", Message: "App {{ .Version }}"}},
+			wantName:    "app-{{ .Version }}",
+			wantMessage: "App {{ .Version }}",
+		},
+		{
+			name:        "Flags override config",
+			config:      &utils.Config{Tag: utils.Tag{Name: "app-{{ .Version }}", Message: "App {{ .Version }}"}},
+			params:      myParams{varTagName: "{{ .Version }}", varTagMessage: "Version {{ .Version }}"},
+			wantName:    "{{ .Version }}",
+			wantMessage: "Version {{ .Version }}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params = tt.params
+			s := &Setup{Config: tt.config}
+			name, message := s.tagTemplates()
+			assertions.Equal(t, tt.wantName, name)
+			assertions.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestSetup_tagPushRecalculates(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	// Local repository with three commits, pushed to a bare "origin"
+	repo := initTestRepository(t, "Initial commit", "Update readme", "Update docs")
+	dir, handler := repo.dir, repo.handler
+
+	bareDir := t.TempDir()
+	bare, err := git.PlainInit(bareDir, true)
+	assertions.NoError(t, err)
+	_, err = handler.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{bareDir}})
+	assertions.NoError(t, err)
+	assertions.NoError(t, handler.Push(&git.PushOptions{RemoteName: "origin"}))
+
+	// Another pipeline released the first commit as v0.0.3 in the meantime
+	_, err = bare.CreateTag("v0.0.3", repo.hashes[0], &git.CreateTagOptions{Tagger: repo.signature(), Message: "Release 0.0.3"})
+	assertions.NoError(t, err)
+
+	assertions.NoError(t, os.Chdir(dir))
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	params = myParams{varExisting: true, varPush: true, varRemote: "origin"}
+	s := &Setup{UseLocal: true, LocalConfigFile: filepath.Join(dir, "missing.yaml")}
+	assertions.NoError(t, s.tag())
+
+	assertions.Equal(t, "0.0.5", s.getSemver(), "Version should be recalculated from the remote tag")
+	_, err = bare.Tag("v0.0.5")
+	assertions.NoError(t, err, "Recalculated tag should be pushed")
+	_, err = handler.Tag("v0.0.3")
+	assertions.NoError(t, err, "Remote tags should be fetched")
+}
+
+func TestSetup_tagAtRef(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "Update readme", "Update docs")
+	dir, handler, hashes := repo.dir, repo.handler, repo.hashes
+
+	assertions.NoError(t, os.Chdir(dir))
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	params = myParams{varExisting: true}
+	s := &Setup{UseLocal: true, LocalConfigFile: filepath.Join(dir, "missing.yaml"), Ref: "HEAD~1"}
+	assertions.NoError(t, s.tag())
+
+	// The version calculated at --ref is tagged on that commit, not on HEAD
+	assertions.Equal(t, "0.0.2", s.getSemver())
+	ref, err := handler.Tag("v0.0.2")
+	assertions.NoError(t, err)
+	tag, err := handler.TagObject(ref.Hash())
+	assertions.NoError(t, err)
+	assertions.Equal(t, hashes[1], tag.Target)
+}
+
+func TestSetup_tagPullRequest(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "Update readme")
+	assertions.NoError(t, os.Chdir(repo.dir))
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_REF", "refs/pull/42/merge")
+	t.Setenv("GITHUB_HEAD_REF", "")
+	t.Setenv("GITHUB_SHA", repo.hashes[1].String())
+
+	params = myParams{varExisting: true}
+	s := &Setup{UseLocal: true, LocalConfigFile: filepath.Join(repo.dir, "missing.yaml")}
+	assertions.NoError(t, s.tag())
+	assertions.Equal(t, "42", s.CI.PullRequest)
+	assertions.True(t, params.varDryRun, "pull request builds are dry runs")
+	_, err := repo.handler.Tag("v0.0.2")
+	assertions.Error(t, err, "the version of a pull request build is not tagged")
+
+	params = myParams{varExisting: true}
+	s = &Setup{UseLocal: true, LocalConfigFile: filepath.Join(repo.dir, "missing.yaml")}
+	assertions.NoError(t, s.release())
+	_, err = repo.handler.Tag("v0.0.2")
+	assertions.Error(t, err, "the version of a pull request build is not released")
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// IsGitHubActions reports whether the application runs as a GitHub Actions step
+func IsGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// WriteActionsOutputs appends the step outputs to the $GITHUB_OUTPUT file
+func WriteActionsOutputs(report ReleaseReport) error {
+	var b strings.Builder
+	for _, output := range report.Outputs() {
+		fmt.Fprintf(&b, "%s=%s\n", output.Name, output.Value)
+	}
+	return appendToEnvFile("GITHUB_OUTPUT", b.String())
+}
+
+// WriteActionsSummary appends the Markdown explanation of the release to the $GITHUB_STEP_SUMMARY file
+func WriteActionsSummary(report ReleaseReport, skipped []SkippedTag) error {
+	return appendToEnvFile("GITHUB_STEP_SUMMARY", ActionsSummary(report, skipped))
+}
+
+// ActionsSummary renders the Markdown explanation of the calculated version
+func ActionsSummary(report ReleaseReport, skipped []SkippedTag) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Semantic version: %s\n\n", FormatSemver(report.Version))
+
+	previous := "_none_"
+	if report.PreviousTag != "" {
+		previous = fmt.Sprintf("%s (`%s`)", report.PreviousVersionString(), report.PreviousTag)
+	}
+	b.WriteString("| | |\n| --- | --- |\n")
+	fmt.Fprintf(&b, "| Previous version | %s |\n", previous)
+	fmt.Fprintf(&b, "| Bump | %s |\n", report.Bump())
+	fmt.Fprintf(&b, "| Release needed | %t |\n", report.ReleaseNeeded())
+	fmt.Fprintf(&b, "| Commits since previous version | %d |\n", len(report.Commits))
+
+	if len(report.Commits) > 0 {
+		b.WriteString("\n### Commits\n\n| Commit | Message | Bump |\n| --- | --- | --- |\n")
+		for i, commit := range report.Commits {
+			subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", shortHash(commit.Hash), markdownTableCell(subject), report.Bumps[i])
+		}
+	}
+
+	if len(skipped) > 0 {
+		b.WriteString("\n### Skipped tags\n\n| Tag | Reason |\n| --- | --- |\n")
+		for _, tag := range skipped {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", tag.Name, markdownTableCell(tag.Reason))
+		}
+	}
+	return b.String()
+}
+
+// ActionsWarning prints a workflow command creating a warning annotation
+func ActionsWarning(title string, message string) {
+	fmt.Printf("::warning title=%s::%s\n", escapeActionsProperty(title), escapeActionsData(message))
+}
+
+// appendToEnvFile appends the content to the file named by the environment variable
+func appendToEnvFile(env string, content string) error {
+	path := os.Getenv(env)
+	if path == "" {
+		return fmt.Errorf("%s is not set", env)
+	}
+	// #nosec G304 -- path is provided by the GitHub Actions runner
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", env, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("unable to write %s: %w", env, err)
+	}
+	return nil
+}
+
+// escapeActionsData escapes the message of a workflow command
+func escapeActionsData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeActionsProperty escapes a property value of a workflow command
+func escapeActionsProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// markdownTableCell makes the text safe to use inside a Markdown table cell
+func markdownTableCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteActionsOutputs(t *testing.T) {
+	InitLogger(false)
+
+	outputPath := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+
+	report := NewReleaseReport(SemVer{Major: 1, Minor: 4, Release: 2, EnableReleaseCandidate: true}, "v1.3.7", nil, nil, Wording{}, nil, nil)
+	assert.NoError(t, WriteActionsOutputs(report))
+
+	content, err := os.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "version=1.4.0-rc.2\nmajor=1\nminor=4\npatch=0\nprerelease=rc.2\nprevious_version=1.3.7\nbump=minor\nrelease_needed=true\n", string(content))
+
+	t.Setenv("GITHUB_OUTPUT", "")
+	assert.Error(t, WriteActionsOutputs(report))
+}
+
+func TestActionsSummary(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{Patch: []string{"fix"}}
+	commits := []CommitDetails{{Hash: "0123456789abcdef", Message: "fix: escape | in tables\n\nDetails"}}
+	report := NewReleaseReport(SemVer{Patch: 2}, "v0.0.1", nil, commits, wording, nil, nil)
+
+	summary := ActionsSummary(report, []SkippedTag{{Name: "v0.0.9", Reason: "unsigned lightweight tag"}})
+	assert.Contains(t, summary, "## Semantic version: 0.0.2")
+	assert.Contains(t, summary, "| Previous version | 0.0.1 (`v0.0.1`) |")
+	assert.Contains(t, summary, "| Bump | patch |")
+	assert.Contains(t, summary, "| `0123456` | fix: escape \\| in tables | patch |")
+	assert.Contains(t, summary, "| `v0.0.9` | unsigned lightweight tag |")
+	assert.NotContains(t, summary, "Details")
+}
+
+func TestActionsWarning(t *testing.T) {
+	stdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	ActionsWarning("Skipped tag", "100% wrong\nsecond line")
+	w.Close()
+	os.Stdout = stdout
+
+	out, _ := io.ReadAll(r)
+	assert.Equal(t, "::warning title=Skipped tag::100%25 wrong%0Asecond line\n", string(out))
+}
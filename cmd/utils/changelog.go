@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultChangelogTemplate renders the changelog as Markdown
+const DefaultChangelogTemplate = `## {{ .Version }} ({{ .Date }})
+{{ range .Sections }}
+### {{ .Title }}
+
+{{ range .Entries }}- {{ .Subject }}{{ if .URL }} ([{{ .ShortHash }}]({{ .URL }})){{ else }} ({{ .ShortHash }}){{ end }}
+{{ end }}{{ end }}`
+
+// ChangelogTitles represents the section titles of the changelog
+type ChangelogTitles struct {
+	Breaking string
+	Features string
+	Fixes    string
+	Other    string
+}
+
+// ChangelogSettings represents changelog generation settings
+type ChangelogSettings struct {
+	Titles   ChangelogTitles
+	Template string // Path to a text/template file overriding the default layout
+	File     string // Keep a Changelog file updated in place (e.g. CHANGELOG.md)
+}
+
+// ChangelogEntry represents a single commit in the changelog
+type ChangelogEntry struct {
+	Subject   string
+	Body      string
+	Hash      string
+	ShortHash string
+	URL       string
+	Author    string
+	Bump      Bump
+}
+
+// ChangelogSection represents a group of commits triggering the same bump level
+type ChangelogSection struct {
+	Title   string
+	Entries []ChangelogEntry
+}
+
+// Changelog holds the data available to the changelog template
+type Changelog struct {
+	Version  string
+	Date     string
+	Sections []ChangelogSection // Non-empty sections in Breaking, Features, Fixes, Other order
+	Breaking []ChangelogEntry
+	Features []ChangelogEntry
+	Fixes    []ChangelogEntry
+	Other    []ChangelogEntry
+}
+
+// defaultChangelogTitles are used for section titles missing in the configuration
+var defaultChangelogTitles = ChangelogTitles{
+	Breaking: "Breaking changes",
+	Features: "Features",
+	Fixes:    "Fixes",
+	Other:    "Other",
+}
+
+// CommitsSinceLatestTag returns the commits after the latest tagged commit, or all commits when none is tagged
+func CommitsSinceLatestTag(commits []CommitDetails, tags []TagDetails) []CommitDetails {
+	latestTagIndex, _ := LatestTagIndex(commits, tags)
+	return commits[latestTagIndex+1:]
+}
+
+// BuildChangelog groups the commits by the bump level they trigger.
+// Blacklisted commits are left out, as noise such as merge commits is what the blacklist is for, even though
+// outside strict mode they still increment the patch version like any other commit.
+// With rulesAt set, each commit is classified with its own rules instead of wording and blacklist.
+func BuildChangelog(version string, commits []CommitDetails, wording Wording, blacklist []string, rulesAt RulesAt, titles ChangelogTitles, repositoryURL string) Changelog {
+	titles = withDefaultTitles(titles)
+	changelog := Changelog{
+		Version: version,
+		Date:    time.Now().Format("2006-01-02"),
+	}
+
+	// Newest commits first, as they are usually read
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		commitWording, commitBlacklist := rulesAt.Of(commit.Hash, wording, blacklist)
+		if IsBlacklisted(commit.Message, commitBlacklist) {
+			continue
+		}
+
+		subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		entry := ChangelogEntry{
+			Subject:   strings.TrimSpace(subject),
+			Body:      strings.TrimSpace(body),
+			Hash:      commit.Hash,
+			ShortHash: shortHash(commit.Hash),
+			Author:    commit.Author,
+			Bump:      ClassifyCommit(commit.Message, commitWording, commitBlacklist),
+		}
+		if repositoryURL != "" {
+			entry.URL = repositoryURL + "/commit/" + commit.Hash
+		}
+
+		switch entry.Bump {
+		case BumpMajor:
+			changelog.Breaking = append(changelog.Breaking, entry)
+		case BumpMinor:
+			changelog.Features = append(changelog.Features, entry)
+		case BumpPatch:
+			changelog.Fixes = append(changelog.Fixes, entry)
+		default:
+			changelog.Other = append(changelog.Other, entry)
+		}
+	}
+
+	for _, section := range []ChangelogSection{
+		{Title: titles.Breaking, Entries: changelog.Breaking},
+		{Title: titles.Features, Entries: changelog.Features},
+		{Title: titles.Fixes, Entries: changelog.Fixes},
+		{Title: titles.Other, Entries: changelog.Other},
+	} {
+		if len(section.Entries) > 0 {
+			changelog.Sections = append(changelog.Sections, section)
+		}
+	}
+	return changelog
+}
+
+// RenderChangelog renders the changelog with the template file, or the default template when empty
+func RenderChangelog(changelog Changelog, templateFile string) (string, error) {
+	tpl := DefaultChangelogTemplate
+	if templateFile != "" {
+		// #nosec G304 -- path comes from the user's own configuration
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read changelog template: %w", err)
+		}
+		tpl = string(content)
+	}
+
+	t, err := template.New("changelog").Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse changelog template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, changelog); err != nil {
+		return "", fmt.Errorf("unable to render changelog: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RepositoryWebURL converts a git remote URL into the web URL of the repository,
+// e.g. git@github.com:owner/repo.git becomes https://github.com/owner/repo.
+// Returns an empty string for local paths.
+func RepositoryWebURL(remoteURL string) string {
+	remoteURL = strings.TrimSuffix(strings.TrimSpace(remoteURL), "/")
+	remoteURL = strings.TrimSuffix(remoteURL, ".git")
+
+	switch {
+	case strings.HasPrefix(remoteURL, "https://"), strings.HasPrefix(remoteURL, "http://"):
+		// Drop credentials embedded in the URL
+		scheme, rest, _ := strings.Cut(remoteURL, "://")
+		if at := strings.Index(rest, "@"); at != -1 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:]
+		}
+		return scheme + "://" + rest
+	case strings.HasPrefix(remoteURL, "ssh://"):
+		rest := strings.TrimPrefix(remoteURL, "ssh://")
+		if at := strings.Index(rest, "@"); at != -1 {
+			rest = rest[at+1:]
+		}
+		host, path, _ := strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host, ":")
+		return "https://" + host + "/" + path
+	case strings.Contains(remoteURL, "@") && strings.Contains(remoteURL, ":"):
+		// scp-like syntax: git@github.com:owner/repo
+		rest := remoteURL[strings.Index(remoteURL, "@")+1:]
+		host, path, _ := strings.Cut(rest, ":")
+		return "https://" + host + "/" + path
+	default:
+		return ""
+	}
+}
+
+// withDefaultTitles fills in section titles missing in the configuration
+func withDefaultTitles(titles ChangelogTitles) ChangelogTitles {
+	if titles.Breaking == "" {
+		titles.Breaking = defaultChangelogTitles.Breaking
+	}
+	if titles.Features == "" {
+		titles.Features = defaultChangelogTitles.Features
+	}
+	if titles.Fixes == "" {
+		titles.Fixes = defaultChangelogTitles.Fixes
+	}
+	if titles.Other == "" {
+		titles.Other = defaultChangelogTitles.Other
+	}
+	return titles
+}
+
+// shortHash returns the abbreviated commit hash
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
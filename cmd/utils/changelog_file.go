@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// keepAChangelogHeader starts a changelog file created from scratch
+const keepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+var (
+	unreleasedHeading = regexp.MustCompile(`(?i)^##\s+\[?unreleased\]?\s*$`)
+	linkDefinition    = regexp.MustCompile(`^\[([^\]]+)\]:\s*\S+`)
+)
+
+// ChangelogRelease holds the tags used to build the compare links of a release
+type ChangelogRelease struct {
+	Tag           string // Tag of the new version
+	PreviousTag   string // Tag of the previous version, empty for the first release
+	RepositoryURL string // Web URL of the repository, links are skipped when empty
+}
+
+// UpdateChangelogFile inserts the changelog as a new version section of a Keep a Changelog file,
+// creating the file when it does not exist. Returns false when the file already has a section
+// for the version, so re-running for the same version leaves the file untouched.
+func UpdateChangelogFile(path string, changelog Changelog, release ChangelogRelease) (bool, error) {
+	// #nosec G304 -- path comes from the user's own configuration
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("unable to read changelog: %w", err)
+	}
+
+	updated, changed := InsertChangelogSection(string(content), changelog, release)
+	if !changed {
+		Debug("Changelog already contains version", map[string]interface{}{
+			"file":    path,
+			"version": changelog.Version,
+		})
+		return false, nil
+	}
+
+	// #nosec G306 -- changelog is a regular repository file
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		return false, fmt.Errorf("unable to write changelog: %w", err)
+	}
+	return true, nil
+}
+
+// InsertChangelogSection returns the Keep a Changelog content with a new section for the version.
+// Notes written by hand under "Unreleased" are moved into the new section and compare links
+// at the bottom of the file are updated. Returns false when the version is already present.
+func InsertChangelogSection(content string, changelog Changelog, release ChangelogRelease) (string, bool) {
+	versionHeading := regexp.MustCompile(`^##\s+\[?` + regexp.QuoteMeta(changelog.Version) + `\]?(\s|$)`)
+
+	if strings.TrimSpace(content) == "" {
+		content = keepAChangelogHeader + "\n## [Unreleased]\n"
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	for _, line := range lines {
+		if versionHeading.MatchString(line) {
+			return content, false
+		}
+	}
+
+	// Locate the Unreleased block and the end of it
+	unreleased := -1
+	for i, line := range lines {
+		if unreleasedHeading.MatchString(line) {
+			unreleased = i
+			break
+		}
+	}
+	blockStart := 0
+	if unreleased >= 0 {
+		blockStart = unreleased + 1
+	}
+	blockEnd := len(lines)
+	for i := blockStart; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") || linkDefinition.MatchString(lines[i]) {
+			blockEnd = i
+			break
+		}
+	}
+
+	var head, notes []string
+	if unreleased >= 0 {
+		head = lines[:unreleased+1]
+		notes = trimBlankLines(lines[blockStart:blockEnd])
+	} else {
+		// Without an Unreleased heading the preamble ends at the first version or link
+		head = append(append([]string{}, trimBlankLines(lines[:blockEnd])...), "", "## [Unreleased]")
+	}
+	tail := trimBlankLines(lines[blockEnd:])
+
+	var section []string
+	section = append(section, fmt.Sprintf("## [%s] - %s", changelog.Version, changelog.Date), "")
+	if len(notes) > 0 {
+		section = append(section, notes...)
+		section = append(section, "")
+	}
+	for _, s := range changelog.Sections {
+		section = append(section, "### "+s.Title, "")
+		for _, entry := range s.Entries {
+			section = append(section, "- "+entry.Subject)
+		}
+		section = append(section, "")
+	}
+
+	var result []string
+	for _, part := range [][]string{trimBlankLines(head), trimBlankLines(section), updateCompareLinks(tail, changelog.Version, release)} {
+		if len(part) == 0 {
+			continue
+		}
+		if len(result) > 0 {
+			result = append(result, "")
+		}
+		result = append(result, part...)
+	}
+	return strings.Join(result, "\n") + "\n", true
+}
+
+// updateCompareLinks points the Unreleased link at the new tag and adds the link of the new version
+func updateCompareLinks(lines []string, version string, release ChangelogRelease) []string {
+	if release.RepositoryURL == "" || release.Tag == "" {
+		return lines
+	}
+
+	unreleasedLink := fmt.Sprintf("[Unreleased]: %s/compare/%s...HEAD", release.RepositoryURL, release.Tag)
+	versionLink := fmt.Sprintf("[%s]: %s/releases/tag/%s", version, release.RepositoryURL, release.Tag)
+	if release.PreviousTag != "" {
+		versionLink = fmt.Sprintf("[%s]: %s/compare/%s...%s", version, release.RepositoryURL, release.PreviousTag, release.Tag)
+	}
+
+	// New links go first in the link block, replacing the previous Unreleased link
+	var result []string
+	inserted := false
+	for _, line := range lines {
+		match := linkDefinition.FindStringSubmatch(line)
+		if match == nil {
+			result = append(result, line)
+			continue
+		}
+		if !inserted {
+			result = append(result, unreleasedLink, versionLink)
+			inserted = true
+		}
+		if strings.EqualFold(match[1], "unreleased") || match[1] == version {
+			continue
+		}
+		result = append(result, line)
+	}
+	if !inserted {
+		if len(result) > 0 {
+			result = append(result, "")
+		}
+		result = append(result, unreleasedLink, versionLink)
+	}
+	return result
+}
+
+// trimBlankLines removes leading and trailing empty lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testChangelog(version string) Changelog {
+	changelog := Changelog{
+		Version:  version,
+		Date:     "2021-02-01",
+		Features: []ChangelogEntry{{Subject: "feat: add tag command"}},
+		Fixes:    []ChangelogEntry{{Subject: "fix: crash on empty config"}},
+	}
+	changelog.Sections = []ChangelogSection{
+		{Title: "Features", Entries: changelog.Features},
+		{Title: "Fixes", Entries: changelog.Fixes},
+	}
+	return changelog
+}
+
+func TestInsertChangelogSection(t *testing.T) {
+	release := ChangelogRelease{Tag: "v1.1.0", PreviousTag: "v1.0.0", RepositoryURL: "https://github.com/owner/repo"}
+
+	t.Run("Existing changelog with unreleased notes", func(t *testing.T) {
+		content := `# Changelog
+
+## [Unreleased]
+
+### Security
+
+- Rotate signing keys
+
+## [1.0.0] - 2021-01-01
+
+### Features
+
+- Initial release
+
+[Unreleased]: https://github.com/owner/repo/compare/v1.0.0...HEAD
+[1.0.0]: https://github.com/owner/repo/releases/tag/v1.0.0
+`
+		got, changed := InsertChangelogSection(content, testChangelog("1.1.0"), release)
+		assert.True(t, changed)
+		assert.Equal(t, `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2021-02-01
+
+### Security
+
+- Rotate signing keys
+
+### Features
+
+- feat: add tag command
+
+### Fixes
+
+- fix: crash on empty config
+
+## [1.0.0] - 2021-01-01
+
+### Features
+
+- Initial release
+
+[Unreleased]: https://github.com/owner/repo/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/owner/repo/compare/v1.0.0...v1.1.0
+[1.0.0]: https://github.com/owner/repo/releases/tag/v1.0.0
+`, got)
+
+		again, changed := InsertChangelogSection(got, testChangelog("1.1.0"), release)
+		assert.False(t, changed, "Same version should not be added twice")
+		assert.Equal(t, got, again)
+	})
+
+	t.Run("New changelog", func(t *testing.T) {
+		got, changed := InsertChangelogSection("", testChangelog("0.1.0"), ChangelogRelease{Tag: "v0.1.0", RepositoryURL: "https://github.com/owner/repo"})
+		assert.True(t, changed)
+		assert.Equal(t, keepAChangelogHeader+`
+## [Unreleased]
+
+## [0.1.0] - 2021-02-01
+
+### Features
+
+- feat: add tag command
+
+### Fixes
+
+- fix: crash on empty config
+
+[Unreleased]: https://github.com/owner/repo/compare/v0.1.0...HEAD
+[0.1.0]: https://github.com/owner/repo/releases/tag/v0.1.0
+`, got)
+	})
+
+	t.Run("Changelog without unreleased section and links", func(t *testing.T) {
+		content := `# Changelog
+
+## 1.0.0 - 2021-01-01
+
+- Initial release
+`
+		got, changed := InsertChangelogSection(content, testChangelog("1.1.0"), ChangelogRelease{})
+		assert.True(t, changed)
+		assert.Equal(t, `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2021-02-01
+
+### Features
+
+- feat: add tag command
+
+### Fixes
+
+- fix: crash on empty config
+
+## 1.0.0 - 2021-01-01
+
+- Initial release
+`, got)
+
+		_, changed = InsertChangelogSection(content, testChangelog("1.0.0"), ChangelogRelease{})
+		assert.False(t, changed, "Headings without brackets should be recognised")
+	})
+}
+
+func TestUpdateChangelogFile(t *testing.T) {
+	InitLogger(false)
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	updated, err := UpdateChangelogFile(path, testChangelog("0.1.0"), ChangelogRelease{})
+	assert.NoError(t, err)
+	assert.True(t, updated, "Missing file should be created")
+
+	first, _ := os.ReadFile(path)
+	updated, err = UpdateChangelogFile(path, testChangelog("0.1.0"), ChangelogRelease{})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	second, _ := os.ReadFile(path)
+	assert.Equal(t, string(first), string(second))
+
+	_, err = UpdateChangelogFile(filepath.Join(t.TempDir(), "missing", "CHANGELOG.md"), testChangelog("0.1.0"), ChangelogRelease{})
+	assert.Error(t, err)
+}
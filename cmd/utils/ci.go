@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CI provider names
+const (
+	CIGitHubActions  = "github-actions"
+	CIGitLab         = "gitlab"
+	CIJenkins        = "jenkins"
+	CICircleCI       = "circleci"
+	CIAzurePipelines = "azure-pipelines"
+	CIBuildkite      = "buildkite"
+	CIDrone          = "drone"
+)
+
+var pullRequestRef = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// CIEnvironment describes the build detected from the CI provider's environment variables
+type CIEnvironment struct {
+	Provider    string // Empty when not running in a known CI
+	Branch      string // Source branch, empty for tag builds
+	PullRequest string // Pull (merge) request number, empty outside of pull request builds
+	Commit      string // Full hash of the commit being built
+	Repository  string // Web URL of the repository being built
+}
+
+// Detected reports whether a known CI provider was detected
+func (c CIEnvironment) Detected() bool {
+	return c.Provider != ""
+}
+
+// Builds reports whether the CI build is for the repository with the given remote URL
+func (c CIEnvironment) Builds(remoteURL string) bool {
+	return c.Repository != "" && strings.EqualFold(c.Repository, RepositoryWebURL(remoteURL))
+}
+
+// DetectCI detects the CI provider from its environment variables and reads the branch,
+// pull request number and commit of the build
+func DetectCI() CIEnvironment {
+	var ci CIEnvironment
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		ci = CIEnvironment{
+			Provider: CIGitHubActions,
+			Branch:   os.Getenv("GITHUB_HEAD_REF"),
+			Commit:   os.Getenv("GITHUB_SHA"),
+		}
+		if repository := os.Getenv("GITHUB_REPOSITORY"); repository != "" {
+			server := os.Getenv("GITHUB_SERVER_URL")
+			if server == "" {
+				server = "https://github.com"
+			}
+			ci.Repository = strings.TrimSuffix(server, "/") + "/" + repository
+		}
+		ref := os.Getenv("GITHUB_REF")
+		if ci.Branch == "" && strings.HasPrefix(ref, "refs/heads/") {
+			ci.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		}
+		if match := pullRequestRef.FindStringSubmatch(ref); match != nil {
+			ci.PullRequest = match[1]
+		}
+	case os.Getenv("GITLAB_CI") == "true":
+		ci = CIEnvironment{
+			Provider:    CIGitLab,
+			Branch:      firstEnv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "CI_COMMIT_BRANCH"),
+			PullRequest: os.Getenv("CI_MERGE_REQUEST_IID"),
+			Commit:      os.Getenv("CI_COMMIT_SHA"),
+			Repository:  os.Getenv("CI_PROJECT_URL"),
+		}
+	case os.Getenv("JENKINS_URL") != "":
+		ci = CIEnvironment{
+			Provider:    CIJenkins,
+			Branch:      strings.TrimPrefix(firstEnv("CHANGE_BRANCH", "BRANCH_NAME", "GIT_BRANCH"), "origin/"),
+			PullRequest: os.Getenv("CHANGE_ID"),
+			Commit:      os.Getenv("GIT_COMMIT"),
+			Repository:  os.Getenv("GIT_URL"),
+		}
+	case os.Getenv("CIRCLECI") == "true":
+		ci = CIEnvironment{
+			Provider:    CICircleCI,
+			Branch:      os.Getenv("CIRCLE_BRANCH"),
+			PullRequest: os.Getenv("CIRCLE_PR_NUMBER"),
+			Commit:      os.Getenv("CIRCLE_SHA1"),
+			Repository:  os.Getenv("CIRCLE_REPOSITORY_URL"),
+		}
+		if pr := os.Getenv("CIRCLE_PULL_REQUEST"); ci.PullRequest == "" && pr != "" {
+			ci.PullRequest = pr[strings.LastIndex(pr, "/")+1:]
+		}
+	case strings.EqualFold(os.Getenv("TF_BUILD"), "true"):
+		ci = CIEnvironment{
+			Provider:    CIAzurePipelines,
+			PullRequest: firstEnv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER", "SYSTEM_PULLREQUEST_PULLREQUESTID"),
+			Commit:      os.Getenv("BUILD_SOURCEVERSION"),
+			Repository:  os.Getenv("BUILD_REPOSITORY_URI"),
+		}
+		ref := firstEnv("SYSTEM_PULLREQUEST_SOURCEBRANCH", "BUILD_SOURCEBRANCH")
+		if strings.HasPrefix(ref, "refs/heads/") {
+			ci.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		} else if !strings.HasPrefix(ref, "refs/") {
+			ci.Branch = ref
+		}
+	case os.Getenv("BUILDKITE") == "true":
+		ci = CIEnvironment{
+			Provider:   CIBuildkite,
+			Branch:     os.Getenv("BUILDKITE_BRANCH"),
+			Commit:     os.Getenv("BUILDKITE_COMMIT"),
+			Repository: os.Getenv("BUILDKITE_REPO"),
+		}
+		if pr := os.Getenv("BUILDKITE_PULL_REQUEST"); pr != "false" {
+			ci.PullRequest = pr
+		}
+		// Tag builds report the tag in place of the branch
+		if tag := os.Getenv("BUILDKITE_TAG"); tag != "" && tag == ci.Branch {
+			ci.Branch = ""
+		}
+	case os.Getenv("DRONE") == "true":
+		ci = CIEnvironment{
+			Provider:    CIDrone,
+			Branch:      firstEnv("DRONE_SOURCE_BRANCH", "DRONE_BRANCH"),
+			PullRequest: os.Getenv("DRONE_PULL_REQUEST"),
+			Commit:      firstEnv("DRONE_COMMIT_SHA", "DRONE_COMMIT"),
+			Repository:  firstEnv("DRONE_GIT_HTTP_URL", "DRONE_REPO_LINK"),
+		}
+	default:
+		return ci
+	}
+
+	ci.Repository = RepositoryWebURL(ci.Repository)
+	// Some providers report symbolic commits (e.g. "HEAD") before checkout
+	if !plumbing.IsHash(ci.Commit) {
+		ci.Commit = ""
+	}
+	return ci
+}
+
+// firstEnv returns the value of the first non-empty environment variable
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
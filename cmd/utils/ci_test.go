@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clearCIEnvironment unsets the variables used to detect the CI the tests may be running in
+func clearCIEnvironment(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "JENKINS_URL", "CIRCLECI", "TF_BUILD", "BUILDKITE", "DRONE"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestDetectCI(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want CIEnvironment
+	}{
+		{
+			name: "No CI",
+			want: CIEnvironment{},
+		},
+		{
+			name: "GitHub Actions push",
+			env:  map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF": "refs/heads/feature/login", "GITHUB_SHA": sha, "GITHUB_REPOSITORY": "owner/repo", "GITHUB_HEAD_REF": ""},
+			want: CIEnvironment{Provider: CIGitHubActions, Branch: "feature/login", Commit: sha, Repository: "https://github.com/owner/repo"},
+		},
+		{
+			name: "GitHub Actions pull request",
+			env:  map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF": "refs/pull/42/merge", "GITHUB_HEAD_REF": "fix-typo", "GITHUB_SHA": sha, "GITHUB_REPOSITORY": ""},
+			want: CIEnvironment{Provider: CIGitHubActions, Branch: "fix-typo", PullRequest: "42", Commit: sha},
+		},
+		{
+			name: "GitLab merge request",
+			env:  map[string]string{"GITLAB_CI": "true", "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature", "CI_MERGE_REQUEST_IID": "7", "CI_COMMIT_SHA": sha, "CI_PROJECT_URL": "https://gitlab.com/group/project"},
+			want: CIEnvironment{Provider: CIGitLab, Branch: "feature", PullRequest: "7", Commit: sha, Repository: "https://gitlab.com/group/project"},
+		},
+		{
+			name: "Jenkins",
+			env:  map[string]string{"JENKINS_URL": "https://jenkins", "GIT_BRANCH": "origin/main", "GIT_COMMIT": sha, "GIT_URL": "git@github.com:owner/repo.git"},
+			want: CIEnvironment{Provider: CIJenkins, Branch: "main", Commit: sha, Repository: "https://github.com/owner/repo"},
+		},
+		{
+			name: "CircleCI pull request",
+			env:  map[string]string{"CIRCLECI": "true", "CIRCLE_BRANCH": "feature", "CIRCLE_PULL_REQUEST": "https://github.com/owner/repo/pull/12", "CIRCLE_SHA1": sha},
+			want: CIEnvironment{Provider: CICircleCI, Branch: "feature", PullRequest: "12", Commit: sha},
+		},
+		{
+			name: "Azure Pipelines",
+			env:  map[string]string{"TF_BUILD": "True", "BUILD_SOURCEBRANCH": "refs/heads/release/1.x", "BUILD_SOURCEVERSION": sha},
+			want: CIEnvironment{Provider: CIAzurePipelines, Branch: "release/1.x", Commit: sha},
+		},
+		{
+			name: "Buildkite tag build",
+			env:  map[string]string{"BUILDKITE": "true", "BUILDKITE_BRANCH": "v1.0.0", "BUILDKITE_TAG": "v1.0.0", "BUILDKITE_PULL_REQUEST": "false", "BUILDKITE_COMMIT": "HEAD"},
+			want: CIEnvironment{Provider: CIBuildkite},
+		},
+		{
+			name: "Drone pull request",
+			env:  map[string]string{"DRONE": "true", "DRONE_SOURCE_BRANCH": "feature", "DRONE_BRANCH": "main", "DRONE_PULL_REQUEST": "3", "DRONE_COMMIT_SHA": sha},
+			want: CIEnvironment{Provider: CIDrone, Branch: "feature", PullRequest: "3", Commit: sha},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCIEnvironment(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			assert.Equal(t, tt.want, DetectCI())
+		})
+	}
+}
+
+func TestCIEnvironment_Builds(t *testing.T) {
+	ci := CIEnvironment{Provider: CIGitHubActions, Repository: "https://github.com/owner/repo"}
+	assert.True(t, ci.Builds("https://github.com/Owner/repo.git"))
+	assert.True(t, ci.Builds("git@github.com:owner/repo.git"))
+	assert.False(t, ci.Builds("https://github.com/owner/other"))
+	assert.False(t, CIEnvironment{}.Builds("https://github.com/owner/repo"))
+}
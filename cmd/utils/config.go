@@ -12,8 +12,16 @@ type Wording struct {
 	Minor   []string
 	Major   []string
 	Release []string
+	Gitmoji GitmojiWording
+	Match   string // How keywords are matched, MatchFuzzy when empty
 }
 
+// Keyword matching modes of the wording
+const (
+	MatchFuzzy  = "fuzzy"  // Keywords match words of the message containing their letters in order
+	MatchPrefix = "prefix" // Keywords match the type of the subject or of a footer line, e.g. "fix" in "fix(api): typo"
+)
+
 // Force represents forced versioning settings
 type Force struct {
 	Commit   string
@@ -24,39 +32,61 @@ type Force struct {
 	Strict   bool
 }
 
+// Tag represents tag creation settings
+type Tag struct {
+	Name    string // Template of the tag name (e.g., "v{{ .Version }}")
+	Message string // Template of the annotated tag message
+}
+
 // Config represents the application configuration
 type Config struct {
 	Wording     Wording
 	Force       Force
 	Blacklist   []string
 	TagPrefixes []string // Prefixes to strip from tags before parsing (e.g., "app-", "infra-", "v")
+	Tag         Tag
+	Signing     Signing
+	Changelog   ChangelogSettings
+	Files       []VersionFile // Manifests updated with the version by bump-files
+	Release     Release
 }
 
 // ReadConfig reads the configuration from a file
 func ReadConfig(file string) (*Config, error) {
-	config := &Config{}
+	return LoadConfig(ConfigSources{File: file})
+}
 
-	viper.SetConfigFile(file)
-	err := viper.ReadInConfig()
-	if err != nil {
-		err = fmt.Errorf("fatal error config file: %s", err)
-		return config, err
+// unmarshalConfig reads the sections of the loaded configuration, laid out in the current schema version
+func unmarshalConfig(v *viper.Viper, config *Config) error {
+	if err := v.UnmarshalKey("wording", &config.Wording); err != nil {
+		return fmt.Errorf("error parsing wording config: %w", err)
 	}
-
-	if err := viper.UnmarshalKey("wording", &config.Wording); err != nil {
-		return config, fmt.Errorf("error parsing wording config: %w", err)
+	if err := v.UnmarshalKey("force", &config.Force); err != nil {
+		return fmt.Errorf("error parsing force config: %w", err)
+	}
+	if err := v.UnmarshalKey("blacklist", &config.Blacklist); err != nil {
+		return fmt.Errorf("error parsing blacklist config: %w", err)
+	}
+	if err := v.UnmarshalKey("tag_prefixes", &config.TagPrefixes); err != nil {
+		return fmt.Errorf("error parsing tag_prefixes config: %w", err)
+	}
+	if err := v.UnmarshalKey("tag", &config.Tag); err != nil {
+		return fmt.Errorf("error parsing tag config: %w", err)
+	}
+	if err := v.UnmarshalKey("signing", &config.Signing); err != nil {
+		return fmt.Errorf("error parsing signing config: %w", err)
 	}
-	if err := viper.UnmarshalKey("force", &config.Force); err != nil {
-		return config, fmt.Errorf("error parsing force config: %w", err)
+	if err := v.UnmarshalKey("changelog", &config.Changelog); err != nil {
+		return fmt.Errorf("error parsing changelog config: %w", err)
 	}
-	if err := viper.UnmarshalKey("blacklist", &config.Blacklist); err != nil {
-		return config, fmt.Errorf("error parsing blacklist config: %w", err)
+	if err := v.UnmarshalKey("files", &config.Files); err != nil {
+		return fmt.Errorf("error parsing files config: %w", err)
 	}
-	if err := viper.UnmarshalKey("tag_prefixes", &config.TagPrefixes); err != nil {
-		return config, fmt.Errorf("error parsing tag_prefixes config: %w", err)
+	if err := v.UnmarshalKey("release", &config.Release); err != nil {
+		return fmt.Errorf("error parsing release config: %w", err)
 	}
 
-	return config, nil
+	return nil
 }
 
 // ApplyForcedVersioning applies forced versioning settings to a semantic version
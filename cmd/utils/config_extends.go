@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// List merge strategies of extended configurations
+const (
+	MergeAppend  = "append"  // Items of the extending file are added to the ones of the extended file
+	MergeReplace = "replace" // Items of the extending file replace the ones of the extended file
+)
+
+// ErrRepositoryUnavailable is returned when a configuration extends a repository file before the repository is available
+var ErrRepositoryUnavailable = errors.New("the repository is not available yet")
+
+// Extends names the configuration file a configuration builds upon, given as a path or a mapping
+type Extends struct {
+	Path       string
+	Repository bool // Path within the repository being versioned, instead of relative to the extending file
+	Merge      ExtendsMerge
+}
+
+// ExtendsMerge sets how the lists of the extending file are merged with the extended ones, append when empty
+type ExtendsMerge struct {
+	Wording     string
+	Blacklist   string
+	TagPrefixes string `mapstructure:"tag_prefixes"`
+}
+
+// mergedLists are the sections whose lists are merged according to ExtendsMerge
+var mergedLists = []string{"wording", "blacklist", "tag_prefixes"}
+
+// strategy returns the merge strategy of the section
+func (m ExtendsMerge) strategy(section string) string {
+	var strategy string
+	switch section {
+	case "wording":
+		strategy = m.Wording
+	case "blacklist":
+		strategy = m.Blacklist
+	case "tag_prefixes":
+		strategy = m.TagPrefixes
+	}
+	if strategy == "" {
+		return MergeAppend
+	}
+	return strings.ToLower(strategy)
+}
+
+// parseExtends reads the extends value, either the path alone or a mapping
+func parseExtends(node *yaml.Node) (Extends, error) {
+	var extends Extends
+	switch node.Kind {
+	case yaml.ScalarNode:
+		extends.Path = node.Value
+	case yaml.MappingNode:
+		if path := mappingValue(node, "path"); path != nil {
+			extends.Path = path.Value
+		}
+		if repository := mappingValue(node, "repository"); repository != nil {
+			value, err := strconv.ParseBool(repository.Value)
+			if err != nil {
+				return extends, fmt.Errorf("repository must be true or false, got %q", repository.Value)
+			}
+			extends.Repository = value
+		}
+		merge := mappingValue(node, "merge")
+		for _, section := range mergedLists {
+			value := mappingValue(merge, section)
+			if value == nil {
+				continue
+			}
+			if strategy := strings.ToLower(value.Value); strategy != MergeAppend && strategy != MergeReplace {
+				return extends, fmt.Errorf("merge.%s must be %s or %s, got %q", section, MergeAppend, MergeReplace, value.Value)
+			}
+			switch section {
+			case "wording":
+				extends.Merge.Wording = value.Value
+			case "blacklist":
+				extends.Merge.Blacklist = value.Value
+			case "tag_prefixes":
+				extends.Merge.TagPrefixes = value.Value
+			}
+		}
+	default:
+		return extends, fmt.Errorf("expected a path or a mapping, got %s", describeNode(node))
+	}
+	if strings.TrimSpace(extends.Path) == "" {
+		return extends, errors.New("no path given")
+	}
+	return extends, nil
+}
+
+// resolve returns the path of the extended file. Paths are relative to the directory of the extending file,
+// or to the repository root with Repository set.
+func (e Extends) resolve(file string, repository string) (string, error) {
+	if e.Repository {
+		if repository == "" {
+			return "", fmt.Errorf("%s extends %s of the repository, which can only be read with -l or once the remote repository is cloned: %w",
+				file, e.Path, ErrRepositoryUnavailable)
+		}
+		return filepath.Join(repository, filepath.Clean("/"+e.Path)), nil
+	}
+	if filepath.IsAbs(e.Path) {
+		return e.Path, nil
+	}
+	return filepath.Join(filepath.Dir(file), e.Path), nil
+}
+
+// extendConfigDocument replaces the extends key of the document with the content of the extended files,
+// returning the files it was merged from, outermost first. seen holds the files of the chain, to stop cycles.
+func extendConfigDocument(document *yaml.Node, file string, repository string, read FileReader, seen map[string]bool) ([]string, error) {
+	root := document.Content[0]
+	node := mappingValue(root, "extends")
+	if node == nil {
+		return nil, nil
+	}
+	extends, err := parseExtends(node)
+	if err != nil {
+		return nil, fmt.Errorf("%s: extends: %w", file, err)
+	}
+	path, err := extends.resolve(file, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if seen[absolute] {
+		return nil, fmt.Errorf("%s: extends %s, which extends it back", file, path)
+	}
+	seen[absolute] = true
+
+	parent, err := loadConfigDocument(path, read)
+	if err != nil {
+		return nil, err
+	}
+	files, err := extendConfigDocument(parent, path, repository, read, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := parent.Content[0]
+	removeMappingKey(root, "extends")
+	mergeConfigSections(merged, root, extends.Merge)
+	document.Content[0] = merged
+	return append(files, path), nil
+}
+
+// mergeConfigSections merges the sections of the extending configuration into the extended one.
+// Mappings are merged key by key, the lists of wording, blacklist and tag_prefixes according to the strategy,
+// and any other value of the extending configuration replaces the extended one.
+func mergeConfigSections(base *yaml.Node, override *yaml.Node, merge ExtendsMerge) {
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		section := strings.ToLower(key.Value)
+		current := mappingValue(base, key.Value)
+		switch {
+		case current == nil:
+			// Keeping the key node keeps its comments
+			base.Content = append(base.Content, key, value)
+		case section == "wording" && current.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				level, keywords := value.Content[j], value.Content[j+1]
+				if existing := mappingValue(current, level.Value); existing != nil && existing.Kind == yaml.SequenceNode {
+					setMappingValue(current, level.Value, mergeLists(existing, keywords, merge.strategy(section)))
+					continue
+				}
+				setMappingValue(current, level.Value, mergeNodes(mappingValue(current, level.Value), keywords))
+			}
+		case section == "blacklist" || section == "tag_prefixes":
+			setMappingValue(base, key.Value, mergeLists(current, value, merge.strategy(section)))
+		default:
+			setMappingValue(base, key.Value, mergeNodes(current, value))
+		}
+	}
+}
+
+// mergeLists appends the items of the extending list missing from the extended one, or replaces it
+func mergeLists(base *yaml.Node, override *yaml.Node, strategy string) *yaml.Node {
+	if strategy == MergeReplace || base.Kind != yaml.SequenceNode || override.Kind != yaml.SequenceNode {
+		return override
+	}
+	merged := *base
+	merged.Content = append([]*yaml.Node{}, base.Content...)
+	for _, item := range override.Content {
+		duplicate := false
+		for _, existing := range base.Content {
+			if item.Kind == yaml.ScalarNode && existing.Kind == yaml.ScalarNode && strings.EqualFold(item.Value, existing.Value) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged.Content = append(merged.Content, item)
+		}
+	}
+	return &merged
+}
+
+// mergeNodes merges mappings key by key, any other value of the override replaces the base
+func mergeNodes(base *yaml.Node, override *yaml.Node) *yaml.Node {
+	if base == nil || base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		current := mappingValue(base, key.Value)
+		if current == nil {
+			base.Content = append(base.Content, key, value)
+			continue
+		}
+		setMappingValue(base, key.Value, mergeNodes(current, value))
+	}
+	return base
+}
+
+// removeMappingKey removes the key and its value from the mapping node
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
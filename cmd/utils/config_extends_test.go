@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig_extends(t *testing.T) {
+	InitLogger(false)
+
+	dir := t.TempDir()
+	org := filepath.Join(dir, "org", "semver.yaml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(org), 0o750))
+	assert.NoError(t, os.WriteFile(org, []byte(`version: 1
+force:
+  existing: true
+  strict: true
+blacklist: ["Merge branch"]
+tag_prefixes: ["app-"]
+wording:
+  patch: [fix, perf]
+  minor: [feat]
+  major: [BREAKING]
+`), 0o600))
+
+	tests := []struct {
+		name      string
+		content   string
+		wantErr   string
+		wantCheck func(t *testing.T, config *Config)
+	}{
+		{
+			name:    "Lists are appended by default",
+			content: "extends: org/semver.yaml\nblacklist: [wip]\nwording:\n  patch: [fix, revert]\n  release: [rc]\nforce:\n  strict: false\n",
+			wantCheck: func(t *testing.T, config *Config) {
+				assert.Equal(t, []string{"fix", "perf", "revert"}, config.Wording.Patch)
+				assert.Equal(t, []string{"feat"}, config.Wording.Minor)
+				assert.Equal(t, []string{"rc"}, config.Wording.Release)
+				assert.Equal(t, []string{"Merge branch", "wip"}, config.Blacklist)
+				assert.Equal(t, []string{"app-"}, config.TagPrefixes)
+				assert.True(t, config.Force.Existing, "keys of a section missing from the file are inherited")
+				assert.False(t, config.Force.Strict)
+			},
+		},
+		{
+			name: "Lists replaced by strategy",
+			content: `extends:
+  path: org/semver.yaml
+  merge:
+    wording: replace
+    tag_prefixes: replace
+blacklist: [wip]
+tag_prefixes: [infra-]
+wording:
+  patch: [fix]
+`,
+			wantCheck: func(t *testing.T, config *Config) {
+				assert.Equal(t, []string{"fix"}, config.Wording.Patch)
+				assert.Equal(t, []string{"feat"}, config.Wording.Minor, "levels missing from the file are inherited")
+				assert.Equal(t, []string{"Merge branch", "wip"}, config.Blacklist)
+				assert.Equal(t, []string{"infra-"}, config.TagPrefixes)
+			},
+		},
+		{
+			name:    "Repository file",
+			content: "extends:\n  path: /org/semver.yaml\n  repository: true\n",
+			wantCheck: func(t *testing.T, config *Config) {
+				assert.Equal(t, []string{"feat"}, config.Wording.Minor)
+			},
+		},
+		{name: "Missing file", content: "extends: org/missing.yaml\n", wantErr: "no such file"},
+		{name: "Cycle", content: "extends: semver.yaml\n", wantErr: "which extends it back"},
+		{name: "Unknown strategy", content: "extends:\n  path: org/semver.yaml\n  merge:\n    blacklist: prepend\n", wantErr: `merge.blacklist must be append or replace, got "prepend"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "semver.yaml")
+			assert.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+
+			config, err := LoadConfig(ConfigSources{File: file, Repository: dir})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			tt.wantCheck(t, config)
+		})
+	}
+
+	t.Run("Repository not available", func(t *testing.T) {
+		file := filepath.Join(dir, "semver.yaml")
+		assert.NoError(t, os.WriteFile(file, []byte("extends:\n  path: org/semver.yaml\n  repository: true\n"), 0o600))
+		_, err := LoadConfig(ConfigSources{File: file})
+		assert.ErrorIs(t, err, ErrRepositoryUnavailable)
+	})
+}
+
+func TestMergeConfig_extends(t *testing.T) {
+	InitLogger(false)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	assert.NoError(t, os.WriteFile(base, []byte("wording:\n  patch: [fix]\n"), 0o600))
+	shared := filepath.Join(dir, "shared.yaml")
+	assert.NoError(t, os.WriteFile(shared, []byte("extends: base.yaml\nwording:\n  minor: [feat]\n"), 0o600))
+	file := filepath.Join(dir, "semver.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("extends: shared.yaml\n# Our own keyword\nblacklist: [wip]\n"), 0o600))
+
+	content, applied, err := MergeConfig(ConfigSources{File: file})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{base, shared, file}, applied)
+	assert.Equal(t, "version: 1\nwording:\n  patch: [fix]\n  minor: [feat]\n# Our own keyword\nblacklist: [wip]\n", string(content))
+}
+
+func TestValidateConfig_extends(t *testing.T) {
+	dir := t.TempDir()
+	org := filepath.Join(dir, "org.yaml")
+	assert.NoError(t, os.WriteFile(org, []byte("wording:\n  patch: [fix]\n  majr: [breaking]\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "clean.yaml"), []byte("wording:\n  patch: [fix]\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "Path", content: "extends: clean.yaml\nwording:\n  minor: [feat]\n"},
+		{
+			name:    "Issues of the extended file",
+			content: "wording:\n  minor: [feat]\n  patch: [fix, fix]\nextends: org.yaml\n",
+			want: []string{
+				"semver.yaml:3:16: warning: wording.patch[1]: duplicate keyword \"fix\", already listed on line 3",
+				"org.yaml:3:3: wording.majr: unknown key \"majr\", did you mean \"major\"?",
+			},
+		},
+		{
+			name:    "Invalid mapping",
+			content: "extends:\n  path: org.yaml\n  repositry: true\n  merge:\n    blacklist: prepend\n",
+			want: []string{
+				"semver.yaml:2:3: extends: merge.blacklist must be append or replace, got \"prepend\"",
+				"semver.yaml:3:3: extends.repositry: unknown key \"repositry\", did you mean \"repository\"?",
+			},
+		},
+		{name: "Missing file", content: "extends: missing.yaml\n", want: []string{"semver.yaml:1:10: extends: unable to read " + filepath.Join(dir, "missing.yaml") + ": open " + filepath.Join(dir, "missing.yaml") + ": no such file or directory"}},
+		{name: "Cycle", content: "extends: semver.yaml\n", want: []string{"semver.yaml:1:10: extends: " + filepath.Join(dir, "semver.yaml") + " extends this file back"}},
+		{name: "Repository files are checked when loaded", content: "extends:\n  path: missing.yaml\n  repository: true\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "semver.yaml")
+			assert.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+
+			issues, err := ValidateConfig(file)
+			assert.NoError(t, err)
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.String()[len(dir)+1:])
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
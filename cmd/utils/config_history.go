@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CommitRules are the wording and blacklist a commit is classified with
+type CommitRules struct {
+	Wording   Wording
+	Blacklist []string
+}
+
+// RulesAt returns the rules of the commit with the hash. A nil RulesAt classifies every commit with the configuration.
+type RulesAt func(hash string) CommitRules
+
+// Of returns the rules of the commit, the given wording and blacklist when r is nil
+func (r RulesAt) Of(hash string, wording Wording, blacklist []string) (Wording, []string) {
+	if r == nil {
+		return wording, blacklist
+	}
+	rules := r(hash)
+	return rules.Wording, rules.Blacklist
+}
+
+// historicalConfig holds the rules read from a commit, with the files they were read from
+type historicalConfig struct {
+	blobs map[string]plumbing.Hash // Blob of each file read by path in the tree, zero for missing files
+	rules CommitRules
+}
+
+// matches reports whether the tree holds the files the rules were read from unchanged
+func (c historicalConfig) matches(tree *object.Tree) bool {
+	for path, hash := range c.blobs {
+		if treeBlob(tree, path) != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// treeBlob returns the blob hash of the file in the tree, zero when it is missing
+func treeBlob(tree *object.Tree, path string) plumbing.Hash {
+	entry, err := tree.FindEntry(path)
+	if err != nil || !entry.Mode.IsFile() {
+		return plumbing.ZeroHash
+	}
+	return entry.Hash
+}
+
+// HistoricalRules classifies every commit with the configuration file as it is in the tree of that commit,
+// so changing the wording does not change the versions calculated for older commits. The first of the files
+// found at a commit is used, with the files it extends read from the same tree and the environment and the
+// overrides of sources applied on top of it. Commits without any of them, with a configuration which can not
+// be read or missing from the repository (e.g. hypothetical ones) use the fallback rules.
+func HistoricalRules(repo *GitRepository, files []string, sources ConfigSources, fallback CommitRules) RulesAt {
+	// Most commits do not change the configuration, it is only read again when the configuration file
+	// or one of the files it extends differs from the ones the known rules were read from
+	loaded := make(map[string][]historicalConfig)
+	return func(hash string) CommitRules {
+		if repo.Handler == nil {
+			return fallback
+		}
+		commit, err := repo.Handler.CommitObject(plumbing.NewHash(hash))
+		if err != nil {
+			return fallback
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			return fallback
+		}
+
+		for _, file := range files {
+			if treeBlob(tree, filepath.ToSlash(filepath.Clean(file))).IsZero() {
+				continue
+			}
+			for _, known := range loaded[file] {
+				if known.matches(tree) {
+					return known.rules
+				}
+			}
+
+			config := historicalConfig{blobs: make(map[string]plumbing.Hash), rules: fallback}
+			read := treeFileReader(tree)
+			loadedConfig, err := LoadConfig(ConfigSources{
+				File: file,
+				ReadFile: func(path string) ([]byte, error) {
+					path = filepath.ToSlash(filepath.Clean(path))
+					config.blobs[path] = treeBlob(tree, path)
+					return read(path)
+				},
+				Repository: ".",
+				Env:        sources.Env,
+				Overrides:  sources.Overrides,
+			})
+			if err != nil {
+				Error("Unable to read the configuration of the commit, using the current one", map[string]interface{}{
+					"commit": hash,
+					"file":   file,
+					"error":  err.Error(),
+				})
+			} else {
+				Debug("Using the configuration of the commit", map[string]interface{}{
+					"commit": hash,
+					"file":   file,
+				})
+				config.rules = CommitRules{Wording: loadedConfig.Wording, Blacklist: loadedConfig.Blacklist}
+			}
+			loaded[file] = append(loaded[file], config)
+			return config.rules
+		}
+		return fallback
+	}
+}
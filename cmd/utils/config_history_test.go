@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoricalRules(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	// The repository switches from plain English keywords to Conventional Commits
+	oldConfig := "version: 1\nwording:\n  patch:\n    - update\n  minor:\n    - improve\n"
+	newConfig := "version: 1\nwording:\n  patch:\n    - fix\n  minor:\n    - feat\n"
+	repo := initTestRepository(t, "Initial commit")
+	worktree, err := repo.Handler.Worktree()
+	assert.NoError(t, err)
+	for i, step := range []struct {
+		message string
+		config  string
+	}{
+		{message: "update: add the configuration", config: oldConfig},
+		{message: "improve: login"},
+		{message: "fix: switch to conventional commits", config: newConfig},
+		{message: "feat: export"},
+	} {
+		if step.config != "" {
+			assert.NoError(t, os.WriteFile(filepath.Join(repo.LocalPath, "semver.yaml"), []byte(step.config), 0o600))
+			_, err = worktree.Add("semver.yaml")
+			assert.NoError(t, err)
+		}
+		_, err = worktree.Commit(step.message, &git.CommitOptions{
+			AllowEmptyCommits: true,
+			Author:            &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Date(2021, 1, 2+i, 0, 0, 0, 0, time.UTC)},
+		})
+		assert.NoError(t, err)
+	}
+	commits, err := ListCommits(repo)
+	assert.NoError(t, err)
+
+	current := CommitRules{Wording: Wording{Patch: []string{"fix"}, Minor: []string{"feat"}}}
+	rulesAt := HistoricalRules(repo, ConfigFileNames, ConfigSources{}, current)
+
+	t.Run("Rules of each commit", func(t *testing.T) {
+		assert.Equal(t, current, rulesAt(commits[0].Hash), "no configuration yet")
+		assert.Equal(t, []string{"update"}, rulesAt(commits[1].Hash).Wording.Patch)
+		assert.Equal(t, []string{"improve"}, rulesAt(commits[2].Hash).Wording.Minor)
+		assert.Equal(t, []string{"feat"}, rulesAt(commits[4].Hash).Wording.Minor)
+		assert.Equal(t, current, rulesAt("0123456789012345678901234567890123456789"), "unknown commit")
+		assert.Equal(t, current, HistoricalRules(&GitRepository{}, ConfigFileNames, ConfigSources{}, current)(commits[1].Hash))
+		assert.Empty(t, viper.AllKeys(), "the configurations are read without the global viper")
+	})
+
+	t.Run("Overrides apply to every commit", func(t *testing.T) {
+		rulesAt := HistoricalRules(repo, ConfigFileNames, ConfigSources{
+			Env:       []string{"SEMVER_BLACKLIST=wip"},
+			Overrides: []string{"wording.major=breaking"},
+		}, current)
+		for _, commit := range commits[1:] {
+			assert.Equal(t, []string{"breaking"}, rulesAt(commit.Hash).Wording.Major, commit.Message)
+			assert.Equal(t, []string{"wip"}, rulesAt(commit.Hash).Blacklist, commit.Message)
+		}
+		assert.Equal(t, []string{"update"}, rulesAt(commits[1].Hash).Wording.Patch)
+	})
+
+	t.Run("Historical versions stay stable", func(t *testing.T) {
+		got := CalculateSemver(commits, nil, current.Wording, current.Blacklist, nil, SemVer{}, false, true, nil)
+		assert.Equal(t, "0.1.1", FormatSemver(got), "the old keywords are ignored")
+
+		got = CalculateSemver(commits, nil, current.Wording, current.Blacklist, rulesAt, SemVer{}, false, true, nil)
+		assert.Equal(t, "0.2.1", FormatSemver(got))
+
+		changes := VersionHistory(commits, nil, current.Wording, current.Blacklist, rulesAt, SemVer{}, false, true, nil)
+		if assert.Len(t, changes, 4) {
+			assert.Equal(t, "0.0.1", FormatSemver(changes[0].Version))
+			assert.Equal(t, []string{"update"}, changes[0].Keywords)
+			assert.Equal(t, "0.1.1", FormatSemver(changes[1].Version))
+			assert.Equal(t, "0.2.1", FormatSemver(changes[3].Version))
+		}
+	})
+}
+
+func TestHistoricalRules_extends(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	// Only the extended file changes, the configuration file itself stays the same
+	repo := initTestRepository(t, "Initial commit")
+	worktree, err := repo.Handler.Worktree()
+	assert.NoError(t, err)
+	for i, step := range []struct {
+		file    string
+		content string
+	}{
+		{file: "semver.yaml", content: "version: 1\nextends: base.yaml\n"},
+		{file: "base.yaml", content: "wording:\n  patch:\n    - update\n"},
+		{file: "base.yaml", content: "wording:\n  patch:\n    - fix\n"},
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(repo.LocalPath, step.file), []byte(step.content), 0o600))
+		_, err = worktree.Add(step.file)
+		assert.NoError(t, err)
+		_, err = worktree.Commit("update: "+step.file, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Date(2021, 1, 2+i, 0, 0, 0, 0, time.UTC)},
+		})
+		assert.NoError(t, err)
+	}
+	commits, err := ListCommits(repo)
+	assert.NoError(t, err)
+
+	current := CommitRules{Wording: Wording{Patch: []string{"fix"}}}
+	rulesAt := HistoricalRules(repo, ConfigFileNames, ConfigSources{}, current)
+	assert.Equal(t, current, rulesAt(commits[1].Hash), "extended file missing")
+	assert.Equal(t, []string{"update"}, rulesAt(commits[2].Hash).Wording.Patch)
+	assert.Equal(t, []string{"fix"}, rulesAt(commits[3].Hash).Wording.Patch)
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// CurrentConfigVersion is the newest configuration schema version, written by config migrate
+const CurrentConfigVersion = 1
+
+// ErrUnsupportedConfigVersion is returned for configuration schema versions this release does not know
+var ErrUnsupportedConfigVersion = errors.New("unsupported configuration version")
+
+// configMigrations upgrade a configuration document from the schema version of their index to the next one.
+// The version field itself is updated by migrateDocument.
+var configMigrations = []func(root *yaml.Node){
+	// Files without a version predate the field, their layout is the one of version 1
+	0: func(root *yaml.Node) {},
+}
+
+// checkConfigVersion rejects schema versions newer than the current one
+func checkConfigVersion(version int) error {
+	if version < 0 || version > CurrentConfigVersion {
+		return fmt.Errorf("%w %d, the newest version supported by this release is %d, please upgrade semver-generator",
+			ErrUnsupportedConfigVersion, version, CurrentConfigVersion)
+	}
+	return nil
+}
+
+// documentVersion returns the schema version of the configuration document, 0 when it has none
+func documentVersion(root *yaml.Node) (int, error) {
+	node := mappingValue(root, "version")
+	if node == nil || node.Tag == "!!null" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || node.Kind != yaml.ScalarNode {
+		return 0, fmt.Errorf("version must be an integer, got %q", node.Value)
+	}
+	return version, nil
+}
+
+// migrateDocument upgrades the configuration document to the current schema version in place,
+// returning the version it had
+func migrateDocument(root *yaml.Node) (int, error) {
+	if root.Kind != yaml.MappingNode {
+		return 0, errors.New("configuration is not a mapping")
+	}
+	version, err := documentVersion(root)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkConfigVersion(version); err != nil {
+		return version, err
+	}
+	if version == CurrentConfigVersion {
+		return version, nil
+	}
+
+	for v := version; v < CurrentConfigVersion; v++ {
+		configMigrations[v](root)
+	}
+	setDocumentVersion(root, CurrentConfigVersion)
+	return version, nil
+}
+
+// setDocumentVersion sets the version field, adding it as the first key when missing
+func setDocumentVersion(root *yaml.Node, version int) {
+	value := strconv.Itoa(version)
+	if node := mappingValue(root, "version"); node != nil {
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!int", value
+		return
+	}
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: value},
+	}, root.Content...)
+}
+
+// parseConfigDocument reads the YAML or JSON configuration file, nil for an empty file.
+// The configuration sections are in the mapping node of the returned document's Content[0].
+func parseConfigDocument(file string, read FileReader) (*yaml.Node, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedConfigFormat, file)
+	}
+
+	content, err := read(file)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", file, err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	return &document, nil
+}
+
+// MigrateConfig rewrites the YAML configuration file in the current schema version, returning the new content
+// and the version the file had. Comments are kept.
+func MigrateConfig(file string) ([]byte, int, error) {
+	if ext := strings.ToLower(filepath.Ext(file)); ext != ".yaml" && ext != ".yml" {
+		return nil, 0, fmt.Errorf("only YAML configuration files can be migrated: %s", file)
+	}
+	document, err := parseConfigDocument(file, readFile)
+	if err != nil {
+		return nil, 0, err
+	}
+	if document == nil {
+		document = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	version, err := migrateDocument(document.Content[0])
+	if err != nil {
+		return nil, version, err
+	}
+
+	// Encoding the document keeps the comments at its top
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, version, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, version, err
+	}
+	return b.Bytes(), version, nil
+}
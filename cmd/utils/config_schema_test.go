@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		want        string
+		wantVersion int
+		wantErr     bool
+	}{
+		{
+			name:        "Unversioned file",
+			file:        "semver.yaml",
+			content:     "# Versioning\n\nwording:\n  patch:\n    - fix # bugfixes\n",
+			want:        "# Versioning\n\nversion: 1\nwording:\n  patch:\n    - fix # bugfixes\n",
+			wantVersion: 0,
+		},
+		{
+			name:        "Current version",
+			file:        "semver.yml",
+			content:     "version: 1\nwording:\n  patch: [fix]\n",
+			want:        "version: 1\nwording:\n  patch: [fix]\n",
+			wantVersion: 1,
+		},
+		{
+			name:        "Empty file",
+			file:        "semver.yaml",
+			content:     "",
+			want:        "version: 1\n",
+			wantVersion: 0,
+		},
+		{name: "Future version", file: "semver.yaml", content: "version: 2\n", wantVersion: 2, wantErr: true},
+		{name: "Invalid version", file: "semver.yaml", content: "version: latest\n", wantErr: true},
+		{name: "JSON file", file: "semver.json", content: `{"wording": {"patch": ["fix"]}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			assert.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+
+			content, version, err := MigrateConfig(file)
+			assert.Equal(t, tt.wantVersion, version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+		})
+	}
+}
+
+func TestReadConfigVersions(t *testing.T) {
+	InitLogger(false)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{name: "Unversioned file", content: "wording:\n  patch: [fix]\n"},
+		{name: "Current version", content: "version: 1\nwording:\n  patch: [fix]\n"},
+		{name: "Future version", content: "version: 2\nwording:\n  patch: [fix]\n", wantErr: ErrUnsupportedConfigVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "semver.yaml")
+			assert.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+
+			config, err := ReadConfig(file)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"fix"}, config.Wording.Patch)
+
+			issues, err := ValidateConfig(file)
+			assert.NoError(t, err)
+			assert.Empty(t, issues)
+		})
+	}
+
+	t.Run("Old schema notice is logged once", func(t *testing.T) {
+		defer InitLogger(false)
+		oldSchemaNotice = sync.Once{}
+		var logs bytes.Buffer
+		InitLogger(true).SetOutput(&logs)
+
+		file := filepath.Join(t.TempDir(), "semver.yaml")
+		assert.NoError(t, os.WriteFile(file, []byte("wording:\n  patch: [fix]\n"), 0o600))
+		for i := 0; i < 3; i++ {
+			_, err := ReadConfig(file)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, strings.Count(logs.String(), "old schema version"))
+	})
+
+	t.Run("Future version is a validation issue", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "semver.yaml")
+		assert.NoError(t, os.WriteFile(file, []byte("version: 2\nwordings: {}\n"), 0o600))
+
+		issues, err := ValidateConfig(file)
+		assert.NoError(t, err)
+		if assert.Len(t, issues, 1) {
+			assert.Equal(t, "version", issues[0].Key)
+			assert.Equal(t, 1, issues[0].Line)
+			assert.Contains(t, issues[0].Message, "newest version supported by this release is 1")
+		}
+	})
+}
@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// ConfigFileNames are looked for, in order, in the current directory and its parents
+var ConfigFileNames = []string{"semver.yaml", ".semver.yaml", filepath.Join(".github", "semver.yaml")}
+
+// ConfigEnvPrefix is prepended to the upper-cased configuration keys to override them from the environment,
+// e.g. SEMVER_FORCE_STRICT for force.strict
+const ConfigEnvPrefix = "SEMVER_"
+
+// FileReader reads configuration files, e.g. from the disk or from the tree of a commit
+type FileReader func(path string) ([]byte, error)
+
+// readFile reads configuration files from the disk
+func readFile(path string) ([]byte, error) {
+	// #nosec G304 -- path of the configuration file given by the user
+	return os.ReadFile(path)
+}
+
+// ConfigSources are the layers of the configuration, later ones taking precedence
+type ConfigSources struct {
+	File       string     // Configuration file, none when empty
+	ReadFile   FileReader // Reads File and the files it extends, from the disk when nil
+	Repository string     // Root of the repository being versioned, for files extending one of its files
+	Env        []string   // Environment as returned by os.Environ, SEMVER_* variables override configuration keys
+	Overrides  []string   // key=value pairs, e.g. from --set wording.major=breaking
+}
+
+// configOverride is a configuration key set by an environment variable or a key=value pair
+type configOverride struct {
+	Source string // e.g. SEMVER_FORCE_STRICT or --set force.strict
+	Key    string
+	Value  string
+}
+
+// FindConfigFile looks for the configuration in the directory and its parents, stopping at the root of the repository.
+// The path is returned relative to the directory, empty when there is none.
+func FindConfigFile(dir string) (string, error) {
+	start, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for current := start; ; {
+		for _, name := range ConfigFileNames {
+			path := filepath.Join(current, name)
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				return filepath.Rel(start, path)
+			}
+		}
+
+		// Configurations outside of the repository belong to something else
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", nil
+		}
+		current = parent
+	}
+}
+
+// LoadConfig reads the configuration file, with the environment and the overrides applied on top of it
+func LoadConfig(sources ConfigSources) (*Config, error) {
+	config := &Config{}
+	content, applied, err := MergeConfig(sources)
+	if err != nil {
+		return config, err
+	}
+	Debug("Loaded configuration", map[string]interface{}{
+		"sources": strings.Join(applied, ", "),
+	})
+	return readMergedConfig(content)
+}
+
+// readMergedConfig reads the configuration from the YAML returned by MergeConfig
+func readMergedConfig(content []byte) (*Config, error) {
+	config := &Config{}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return config, fmt.Errorf("fatal error config file: %w", err)
+	}
+	return config, unmarshalConfig(v, config)
+}
+
+// MergeConfig returns the effective configuration of the sources as YAML in the current schema version,
+// keeping the comments of the file, and the sources which were applied.
+func MergeConfig(sources ConfigSources) ([]byte, []string, error) {
+	var applied []string
+	document := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	if sources.File != "" {
+		read := sources.ReadFile
+		if read == nil {
+			read = readFile
+		}
+		var err error
+		if document, err = loadConfigDocument(sources.File, read); err != nil {
+			return nil, nil, err
+		}
+		absolute, err := filepath.Abs(sources.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		extended, err := extendConfigDocument(document, sources.File, sources.Repository, read, map[string]bool{absolute: true})
+		if err != nil {
+			return nil, nil, err
+		}
+		applied = append(append(applied, extended...), sources.File)
+	}
+	root := document.Content[0]
+
+	overrides := envOverrides(sources.Env)
+	for _, pair := range sources.Overrides {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("--set %s: expected key=value", pair)
+		}
+		key = strings.TrimSpace(key)
+		overrides = append(overrides, configOverride{Source: "--set " + key, Key: key, Value: value})
+	}
+	for _, o := range overrides {
+		if err := applyOverride(root, o); err != nil {
+			return nil, nil, err
+		}
+		applied = append(applied, o.Source)
+	}
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, err
+	}
+	return b.Bytes(), applied, nil
+}
+
+// loadConfigDocument reads the configuration file as a document in the current schema version.
+// Formats other than YAML and JSON are read by viper and converted.
+func loadConfigDocument(file string, read FileReader) (*yaml.Node, error) {
+	document, err := parseConfigDocument(file, read)
+	if errors.Is(err, ErrUnsupportedConfigFormat) {
+		document, err = readForeignConfig(file, read)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fatal error config file: %w", err)
+	}
+	if document == nil {
+		document = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	// Dispatch on the schema version, files in older versions are upgraded in memory
+	version, err := migrateDocument(document.Content[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if version < CurrentConfigVersion {
+		// Older files keep working as they are, so the notice is a debug log shown once
+		oldSchemaNotice.Do(func() {
+			Debug("Configuration uses an old schema version, run config migrate to upgrade it", map[string]interface{}{
+				"file":    file,
+				"version": version,
+			})
+		})
+	}
+	return document, nil
+}
+
+// oldSchemaNotice logs the old schema version notice once per process
+var oldSchemaNotice sync.Once
+
+// readForeignConfig reads a configuration file in a format only viper understands, e.g. TOML
+func readForeignConfig(file string, read FileReader) (*yaml.Node, error) {
+	content, err := read(file)
+	if err != nil {
+		return nil, err
+	}
+	v := viper.New()
+	v.SetConfigType(strings.TrimPrefix(filepath.Ext(file), "."))
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+	content, err = yaml.Marshal(v.AllSettings())
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// envOverrides returns the overrides of the SEMVER_* variables of the environment naming a configuration key,
+// sorted by key. Other variables, e.g. the SEMVER_VERSION written by --output, are ignored.
+func envOverrides(env []string) []configOverride {
+	keys := configEnvKeys()
+	var overrides []configOverride
+	for _, variable := range env {
+		name, value, ok := strings.Cut(variable, "=")
+		if !ok || !strings.HasPrefix(name, ConfigEnvPrefix) {
+			continue
+		}
+		if key, ok := keys[name]; ok {
+			overrides = append(overrides, configOverride{Source: name, Key: key, Value: value})
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Key < overrides[j].Key })
+	return overrides
+}
+
+// configEnvKeys maps the environment variables overriding configuration keys to the keys.
+// Only keys holding a value or a list of strings can be overridden. version and signing.key are left out,
+// as SEMVER_VERSION is written by --output and SEMVER_SIGNING_KEY holds the key itself.
+func configEnvKeys() map[string]string {
+	keys := make(map[string]string)
+	var walk func(path string, typ reflect.Type)
+	walk = func(path string, typ reflect.Type) {
+		switch {
+		case typ.Kind() == reflect.Struct:
+			for name, field := range structKeys(typ) {
+				walk(joinKey(path, name), field)
+			}
+		case typ.Kind() == reflect.Map, typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.String:
+			// Map keys and the fields of list items can not be named by a variable
+		case path == "version", path == "signing.key":
+		default:
+			keys[ConfigEnvPrefix+strings.ToUpper(strings.ReplaceAll(path, ".", "_"))] = path
+		}
+	}
+	for name, typ := range configSections {
+		walk(name, typ)
+	}
+	return keys
+}
+
+// applyOverride sets the key of the configuration document to the value, checking both against the schema
+func applyOverride(root *yaml.Node, o configOverride) error {
+	path, typ, err := resolveConfigKey(o.Key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", o.Source, err)
+	}
+	value, err := overrideValue(o.Value, typ)
+	if err != nil {
+		return fmt.Errorf("%s: %w", o.Source, err)
+	}
+	v := &configValidator{file: o.Source}
+	v.checkType(value, o.Key, typ)
+	if len(v.issues) > 0 {
+		return fmt.Errorf("%s: %s", o.Source, v.issues[0].Message)
+	}
+
+	node := root
+	for i, key := range path {
+		if i == len(path)-1 {
+			setMappingValue(node, key, value)
+			break
+		}
+		child := mappingValue(node, key)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setMappingValue(node, key, child)
+		}
+		node = child
+	}
+	return nil
+}
+
+// resolveConfigKey splits the dotted key into the names used by the configuration and returns the type of its value
+func resolveConfigKey(key string) ([]string, reflect.Type, error) {
+	parts := strings.Split(key, ".")
+	typ, ok := configSections[strings.ToLower(parts[0])]
+	if !ok {
+		return nil, nil, unknownKeyError(parts[0], sectionNames())
+	}
+	path := []string{strings.ToLower(parts[0])}
+	for _, part := range parts[1:] {
+		switch typ.Kind() {
+		case reflect.Struct:
+			fields := structKeys(typ)
+			known := make([]string, 0, len(fields))
+			for name := range fields {
+				known = append(known, name)
+			}
+			name, ok := "", false
+			for _, candidate := range known {
+				if strings.EqualFold(candidate, part) {
+					name, ok = candidate, true
+				}
+			}
+			if !ok {
+				return nil, nil, unknownKeyError(part, known)
+			}
+			typ = fields[name]
+			path = append(path, name)
+		case reflect.Map:
+			typ = typ.Elem()
+			path = append(path, part)
+		default:
+			return nil, nil, fmt.Errorf("%s holds a value, it has no key %q", strings.Join(path, "."), part)
+		}
+	}
+	return path, typ, nil
+}
+
+// unknownKeyError describes the unknown key, suggesting the closest known key for typos
+func unknownKeyError(key string, known []string) error {
+	if suggestion := closestKey(key, known); suggestion != "" {
+		return fmt.Errorf("unknown key %q, did you mean %q?", key, suggestion)
+	}
+	return fmt.Errorf("unknown key %q", key)
+}
+
+// overrideValue parses the value given for a key of the type. Strings are taken literally and lists of strings
+// can be given comma-separated, anything else is read as YAML, e.g. true, 3 or [fix, perf].
+func overrideValue(value string, typ reflect.Type) (*yaml.Node, error) {
+	switch {
+	case typ.Kind() == reflect.String:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "["):
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return list, nil
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+		return nil, fmt.Errorf("unable to parse %q: %w", value, err)
+	}
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return document.Content[0], nil
+}
+
+// setMappingValue replaces the value of the key in the mapping node, adding the key when missing
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
@@ -0,0 +1,481 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrUnsupportedConfigFormat is returned when the configuration file can not be validated, e.g. TOML
+var ErrUnsupportedConfigFormat = errors.New("only YAML and JSON configuration files can be validated")
+
+// configSections maps the top-level configuration keys to the types they are read into
+var configSections = map[string]reflect.Type{
+	"version":      reflect.TypeOf(0),
+	"wording":      reflect.TypeOf(Wording{}),
+	"force":        reflect.TypeOf(Force{}),
+	"blacklist":    reflect.TypeOf([]string{}),
+	"tag_prefixes": reflect.TypeOf([]string{}),
+	"tag":          reflect.TypeOf(Tag{}),
+	"signing":      reflect.TypeOf(Signing{}),
+	"changelog":    reflect.TypeOf(ChangelogSettings{}),
+	"files":        reflect.TypeOf([]VersionFile{}),
+	"release":      reflect.TypeOf(Release{}),
+	"extends":      reflect.TypeOf(Extends{}),
+}
+
+// ConfigIssue describes a problem found in the configuration file
+type ConfigIssue struct {
+	File    string
+	Line    int
+	Column  int
+	Key     string // Dotted path of the key, e.g. wording.patch[1]
+	Message string
+	Warning bool // Advisory, the configuration can still be used as it is
+}
+
+// String formats the issue as file:line:column: key: message, warnings as file:line:column: warning: key: message
+func (i ConfigIssue) String() string {
+	if i.Warning {
+		return fmt.Sprintf("%s:%d:%d: warning: %s: %s", i.File, i.Line, i.Column, i.Key, i.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", i.File, i.Line, i.Column, i.Key, i.Message)
+}
+
+// SplitConfigIssues separates the errors, which prevent using the configuration, from the warnings
+func SplitConfigIssues(issues []ConfigIssue) ([]ConfigIssue, []ConfigIssue) {
+	var errs, warnings []ConfigIssue
+	for _, issue := range issues {
+		if issue.Warning {
+			warnings = append(warnings, issue)
+		} else {
+			errs = append(errs, issue)
+		}
+	}
+	return errs, warnings
+}
+
+// ConfigError is returned when the configuration file has errors
+type ConfigError struct {
+	Issues []ConfigIssue
+}
+
+// Error describes the first issue and how many more were found
+func (e *ConfigError) Error() string {
+	if len(e.Issues) == 0 {
+		return "invalid configuration"
+	}
+	msg := "invalid configuration: " + e.Issues[0].String()
+	if len(e.Issues) > 1 {
+		msg += fmt.Sprintf(" (and %d more issues, run config validate to list them)", len(e.Issues)-1)
+	}
+	return msg
+}
+
+// ValidateConfig checks the configuration file for unknown keys, values of the wrong type, empty wording lists,
+// keywords configured for several levels or containing blacklisted terms and invalid force.commit hashes.
+// Syntax errors and unreadable files are returned as an error, problems with the content as issues.
+// Unknown keys, wrong types and unsupported versions are errors, the other issues are warnings.
+func ValidateConfig(file string) ([]ConfigIssue, error) {
+	return validateConfigFile(file, readFile, nil)
+}
+
+// ValidateConfigFrom validates the configuration file read by read, e.g. from the tree of a commit
+func ValidateConfigFrom(file string, read FileReader) ([]ConfigIssue, error) {
+	return validateConfigFile(file, read, nil)
+}
+
+// validateConfigFile validates the file, seen holding the files of the extends chain leading to it
+func validateConfigFile(file string, read FileReader, seen map[string]bool) ([]ConfigIssue, error) {
+	document, err := parseConfigDocument(file, read)
+	if err != nil || document == nil {
+		return nil, err
+	}
+	root := document.Content[0]
+
+	v := &configValidator{file: file, read: read, seen: seen}
+	if root.Kind != yaml.MappingNode {
+		v.report(root, "", "expected a mapping of configuration sections")
+		return v.issues, nil
+	}
+
+	// Older schema versions are checked after the upgrade they get when loaded
+	if _, err := migrateDocument(root); errors.Is(err, ErrUnsupportedConfigVersion) {
+		v.report(mappingValue(root, "version"), "version", err.Error())
+		return v.issues, nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		typ, ok := configSections[key.Value]
+		if !ok {
+			v.unknownKey(key, "", sectionNames())
+			continue
+		}
+		if strings.EqualFold(key.Value, "extends") && value.Kind == yaml.ScalarNode {
+			// The path alone
+			continue
+		}
+		v.checkType(value, key.Value, typ)
+	}
+
+	v.checkWording(mappingValue(root, "wording"), mappingValue(root, "blacklist"))
+	if force := mappingValue(root, "force"); force != nil {
+		if commit := mappingValue(force, "commit"); commit != nil && commit.Kind == yaml.ScalarNode &&
+			commit.Value != "" && !plumbing.IsHash(commit.Value) {
+			v.warn(commit, "force.commit", fmt.Sprintf("%q is not a full 40 character commit hash, calculations would start from the first commit", commit.Value))
+		}
+	}
+
+	v.checkExtends(mappingValue(root, "extends"))
+
+	// Issues of the extended files follow the ones of the file
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if own := v.issues[i].File == file; own != (v.issues[j].File == file) {
+			return own
+		}
+		if v.issues[i].File != file {
+			return false
+		}
+		if v.issues[i].Line != v.issues[j].Line {
+			return v.issues[i].Line < v.issues[j].Line
+		}
+		return v.issues[i].Column < v.issues[j].Column
+	})
+	return v.issues, nil
+}
+
+// configValidator collects the issues found while walking the configuration document
+type configValidator struct {
+	file   string
+	read   FileReader
+	issues []ConfigIssue
+	seen   map[string]bool // Files of the extends chain, to stop cycles
+}
+
+// report records an error, which prevents using the configuration
+func (v *configValidator) report(node *yaml.Node, key string, message string) {
+	v.issues = append(v.issues, ConfigIssue{File: v.file, Line: node.Line, Column: node.Column, Key: key, Message: message})
+}
+
+// warn records an advisory issue, the configuration still works as written
+func (v *configValidator) warn(node *yaml.Node, key string, message string) {
+	v.issues = append(v.issues, ConfigIssue{File: v.file, Line: node.Line, Column: node.Column, Key: key, Message: message, Warning: true})
+}
+
+// unknownKey reports the key, suggesting the closest known key for typos
+func (v *configValidator) unknownKey(key *yaml.Node, parent string, known []string) {
+	message := fmt.Sprintf("unknown key %q", key.Value)
+	if suggestion := closestKey(key.Value, known); suggestion != "" {
+		message += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+	v.report(key, joinKey(parent, key.Value), message)
+}
+
+// checkType reports values which can not be read into the type, descending into structs and slices
+func (v *configValidator) checkType(node *yaml.Node, path string, typ reflect.Type) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			v.report(node, path, "expected a mapping, got "+describeNode(node))
+			return
+		}
+		fields := structKeys(typ)
+		known := make([]string, 0, len(fields))
+		for name := range fields {
+			known = append(known, name)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := lookupKey(fields, key.Value)
+			if !ok {
+				v.unknownKey(key, path, known)
+				continue
+			}
+			v.checkType(value, joinKey(path, key.Value), field)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.report(node, path, "expected a list, got "+describeNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			v.checkType(item, fmt.Sprintf("%s[%d]", path, i), typ.Elem())
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.report(node, path, "expected a mapping, got "+describeNode(node))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.checkType(node.Content[i+1], joinKey(path, node.Content[i].Value), typ.Elem())
+		}
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			v.report(node, path, "expected a string, got "+describeNode(node))
+		}
+	case reflect.Int:
+		if _, err := strconv.Atoi(node.Value); node.Kind != yaml.ScalarNode || err != nil {
+			v.report(node, path, "expected an integer, got "+describeNode(node))
+		}
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			v.report(node, path, "expected true or false, got "+describeNode(node))
+		}
+	}
+}
+
+// checkWording reports empty levels, keywords configured more than once, keywords containing blacklisted terms
+// and unknown matching modes
+func (v *configValidator) checkWording(wording *yaml.Node, blacklist *yaml.Node) {
+	if wording == nil || wording.Kind != yaml.MappingNode {
+		return
+	}
+
+	var terms []string
+	if blacklist != nil && blacklist.Kind == yaml.SequenceNode {
+		for _, term := range blacklist.Content {
+			if term.Kind == yaml.ScalarNode && term.Value != "" {
+				terms = append(terms, term.Value)
+			}
+		}
+	}
+
+	type occurrence struct {
+		level string
+		line  int
+	}
+	levels := structKeys(reflect.TypeOf(Wording{}))
+	seen := make(map[string]occurrence)
+	for i := 0; i+1 < len(wording.Content); i += 2 {
+		key, value := wording.Content[i], wording.Content[i+1]
+		if _, ok := lookupKey(levels, key.Value); !ok || strings.EqualFold(key.Value, "gitmoji") || strings.EqualFold(key.Value, "match") {
+			continue
+		}
+		level := strings.ToLower(key.Value)
+		path := joinKey("wording", key.Value)
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" || value.Kind == yaml.SequenceNode && len(value.Content) == 0 {
+			v.warn(key, path, "no keywords, add some or remove the level")
+			continue
+		}
+		if value.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for j, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				continue
+			}
+			itemPath := fmt.Sprintf("%s[%d]", path, j)
+			keyword := strings.TrimSpace(item.Value)
+			if keyword == "" {
+				v.warn(item, itemPath, "empty keyword")
+				continue
+			}
+
+			normalized := strings.ToLower(keyword)
+			if first, ok := seen[normalized]; ok {
+				if first.level == level {
+					v.warn(item, itemPath, fmt.Sprintf("duplicate keyword %q, already listed on line %d", keyword, first.line))
+				} else {
+					v.warn(item, itemPath, fmt.Sprintf("keyword %q is also a %s keyword (line %d), only the higher level ever matches", keyword, first.level, first.line))
+				}
+			} else {
+				seen[normalized] = occurrence{level: level, line: item.Line}
+			}
+
+			for _, term := range terms {
+				if strings.Contains(normalized, strings.ToLower(term)) {
+					v.warn(item, itemPath, fmt.Sprintf("keyword %q contains the blacklisted term %q, commits matching it are always ignored", keyword, term))
+				}
+			}
+		}
+	}
+
+	if match := mappingValue(wording, "match"); match != nil && match.Kind == yaml.ScalarNode && match.Value != MatchFuzzy && match.Value != MatchPrefix {
+		v.report(match, "wording.match", fmt.Sprintf("unknown matching mode %q, expected %s or %s", match.Value, MatchFuzzy, MatchPrefix))
+	}
+	v.checkGitmoji(mappingValue(wording, "gitmoji"))
+}
+
+// checkGitmoji reports gitmoji table entries which are neither an emoji nor a :shortcode:, or have an unknown level
+func (v *configValidator) checkGitmoji(gitmoji *yaml.Node) {
+	table := mappingValue(gitmoji, "table")
+	if table == nil || table.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(table.Content); i += 2 {
+		key, value := table.Content[i], table.Content[i+1]
+		path := joinKey("wording.gitmoji.table", key.Value)
+		if isASCII(key.Value) && !IsGitmojiShortcode(key.Value) {
+			v.warn(key, path, fmt.Sprintf("%q is neither an emoji nor a :shortcode:", key.Value))
+		}
+		if value.Kind != yaml.ScalarNode {
+			continue
+		}
+		if _, err := ParseBump(value.Value); err != nil {
+			v.warn(value, path, fmt.Sprintf("unknown level %q, expected patch, release, minor, major or none", value.Value))
+		}
+	}
+}
+
+// isASCII reports whether the string has no characters outside of ASCII, so it can not be an emoji
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkExtends reports invalid merge strategies and validates the extended file, whose issues are reported with its name.
+// Files of the repository are only checked when loaded, as the repository may not be available yet.
+func (v *configValidator) checkExtends(node *yaml.Node) {
+	if node == nil {
+		return
+	}
+	extends, err := parseExtends(node)
+	if err != nil {
+		v.report(node, "extends", err.Error())
+		return
+	}
+	if extends.Repository {
+		return
+	}
+	path, err := extends.resolve(v.file, "")
+	if err != nil {
+		return
+	}
+
+	absolute, _ := filepath.Abs(path)
+	if v.seen == nil {
+		current, _ := filepath.Abs(v.file)
+		v.seen = map[string]bool{current: true}
+	}
+	if v.seen[absolute] {
+		v.report(node, "extends", fmt.Sprintf("%s extends this file back", path))
+		return
+	}
+	v.seen[absolute] = true
+
+	issues, err := validateConfigFile(path, v.read, v.seen)
+	if err != nil {
+		v.report(node, "extends", fmt.Sprintf("unable to read %s: %s", path, err))
+		return
+	}
+	v.issues = append(v.issues, issues...)
+}
+
+// structKeys returns the configuration keys of the struct fields, as matched by mapstructure
+func structKeys(typ reflect.Type) map[string]reflect.Type {
+	keys := make(map[string]reflect.Type, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.ToLower(field.Name)
+		if tag, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ","); tag != "" {
+			name = tag
+		}
+		keys[name] = field.Type
+	}
+	return keys
+}
+
+// lookupKey finds the key case-insensitively, like mapstructure does
+func lookupKey(keys map[string]reflect.Type, key string) (reflect.Type, bool) {
+	for name, typ := range keys {
+		if strings.EqualFold(name, key) {
+			return typ, true
+		}
+	}
+	return nil, false
+}
+
+// mappingValue returns the value of the key in the mapping node, nil when missing
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// describeNode names the kind of value for type errors
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return strconv.Quote(node.Value)
+	}
+}
+
+func joinKey(parent string, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func sectionNames() []string {
+	names := make([]string, 0, len(configSections))
+	for name := range configSections {
+		names = append(names, name)
+	}
+	return names
+}
+
+// closestKey returns the known key within two edits of the key, empty when none is close enough
+func closestKey(key string, known []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range known {
+		if distance := editDistance(strings.ToLower(key), candidate); distance < bestDistance ||
+			distance == bestDistance && best != "" && candidate < best {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance of the strings
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "Valid configuration",
+			content: `version: 1
+force:
+  major: 1
+  commit: 0123456789abcdef0123456789abcdef01234567
+  existing: true
+blacklist:
+  - "Merge branch"
+tag_prefixes:
+  - "app-"
+wording:
+  patch: [fix, update]
+  minor: [feat]
+  major: [breaking]
+files:
+  - path: package.json
+    json_path: $.version
+signing:
+  require_signed_tags: false
+`,
+		},
+		{
+			name:    "Unknown keys with suggestions",
+			content: "wordings:\n  patch: [fix]\ntag:\n  nmae: v{{ .Version }}\nfiles:\n  - path: Chart.yaml\n    yamlpath: version\nsomething: else\n",
+			want: []string{
+				`1:1: wordings: unknown key "wordings", did you mean "wording"?`,
+				`4:3: tag.nmae: unknown key "nmae", did you mean "name"?`,
+				`7:5: files[0].yamlpath: unknown key "yamlpath", did you mean "yaml_path"?`,
+				`8:1: something: unknown key "something"`,
+			},
+		},
+		{
+			name:    "Wrong types",
+			content: "force:\n  major: one\n  strict: \"yes\"\nblacklist: Merge branch\ntag: v1\nwording:\n  patch:\n    - [fix]\n",
+			want: []string{
+				`2:10: force.major: expected an integer, got "one"`,
+				`3:11: force.strict: expected true or false, got "yes"`,
+				`4:12: blacklist: expected a list, got "Merge branch"`,
+				`5:6: tag: expected a mapping, got "v1"`,
+				`8:7: wording.patch[0]: expected a string, got a list`,
+			},
+		},
+		{
+			name:    "Wording issues",
+			content: "blacklist:\n  - merge\nwording:\n  patch:\n    - fix\n    - Fix\n    - \"\"\n  minor:\n    - fix\n    - merge request\n  major: []\n  release:\n",
+			want: []string{
+				`6:7: warning: wording.patch[1]: duplicate keyword "Fix", already listed on line 5`,
+				`7:7: warning: wording.patch[2]: empty keyword`,
+				`9:7: warning: wording.minor[0]: keyword "fix" is also a patch keyword (line 5), only the higher level ever matches`,
+				`10:7: warning: wording.minor[1]: keyword "merge request" contains the blacklisted term "merge", commits matching it are always ignored`,
+				`11:3: warning: wording.major: no keywords, add some or remove the level`,
+				`12:3: warning: wording.release: no keywords, add some or remove the level`,
+			},
+		},
+		{
+			name:    "Gitmoji table",
+			content: "wording:\n  gitmoji:\n    enabled: true\n    table:\n      \":recycle:\": patch\n      \"♻️\": none\n      recycle: patch\n      \":memo:\": docs\n      \":bug:\": [patch]\n",
+			want: []string{
+				`7:7: warning: wording.gitmoji.table.recycle: "recycle" is neither an emoji nor a :shortcode:`,
+				`8:17: warning: wording.gitmoji.table.:memo:: unknown level "docs", expected patch, release, minor, major or none`,
+				`9:16: wording.gitmoji.table.:bug:: expected a string, got a list`,
+			},
+		},
+		{
+			name:    "Unknown matching mode",
+			content: "wording:\n  match: exact\n  patch: [fix]\n",
+			want:    []string{`2:10: wording.match: unknown matching mode "exact", expected fuzzy or prefix`},
+		},
+		{
+			name:    "Invalid force.commit",
+			content: "force:\n  commit: abc123\n",
+			want:    []string{`2:11: warning: force.commit: "abc123" is not a full 40 character commit hash, calculations would start from the first commit`},
+		},
+		{
+			name:    "Not a mapping",
+			content: "- wording\n",
+			want:    []string{`1:1: : expected a mapping of configuration sections`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "semver.yaml")
+			assert.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+
+			issues, err := ValidateConfig(file)
+			assert.NoError(t, err)
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.String()[len(file)+1:])
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("Syntax error", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "semver.yaml")
+		assert.NoError(t, os.WriteFile(file, []byte("wording:\n  patch: [fix\n"), 0o600))
+		_, err := ValidateConfig(file)
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := ValidateConfig(filepath.Join(t.TempDir(), "semver.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := ValidateConfig("semver.toml")
+		assert.ErrorIs(t, err, ErrUnsupportedConfigFormat)
+	})
+
+	t.Run("Repository configurations are valid", func(t *testing.T) {
+		for _, file := range []string{"../../config.yaml", "../../config-release.yaml"} {
+			issues, err := ValidateConfig(file)
+			assert.NoError(t, err, file)
+			assert.Empty(t, issues, file)
+		}
+	})
+}
+
+func TestConfigError(t *testing.T) {
+	err := &ConfigError{Issues: []ConfigIssue{
+		{File: "semver.yaml", Line: 1, Column: 1, Key: "wordings", Message: "unknown key"},
+		{File: "semver.yaml", Line: 3, Column: 2, Key: "force.commit", Message: "invalid hash"},
+	}}
+	assert.Equal(t, "invalid configuration: semver.yaml:1:1: wordings: unknown key (and 1 more issues, run config validate to list them)", err.Error())
+}
+
+func TestSplitConfigIssues(t *testing.T) {
+	unknown := ConfigIssue{File: "semver.yaml", Line: 1, Column: 1, Key: "wordings", Message: "unknown key"}
+	commit := ConfigIssue{File: "semver.yaml", Line: 3, Column: 11, Key: "force.commit", Message: "not a full hash", Warning: true}
+	errs, warnings := SplitConfigIssues([]ConfigIssue{commit, unknown})
+	assert.Equal(t, []ConfigIssue{unknown}, errs)
+	assert.Equal(t, []ConfigIssue{commit}, warnings)
+	assert.Equal(t, "semver.yaml:3:11: warning: force.commit: not a full hash", commit.String())
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Environment file formats
+const (
+	OutputDotenv = "dotenv" // GitLab artifacts:reports:dotenv
+	OutputEnv    = "env"    // POSIX shell, can be sourced
+)
+
+// EnvOutputPrefix is prepended to the upper-cased output names, e.g. SEMVER_VERSION
+const EnvOutputPrefix = "SEMVER_"
+
+// plainEnvValue matches values which never need quoting
+var plainEnvValue = regexp.MustCompile(`^[A-Za-z0-9._+:/@-]*$`)
+
+// RenderEnvFile renders the release outputs as environment variables in the given format
+func RenderEnvFile(report ReleaseReport, format string) (string, error) {
+	var quote func(string) string
+	switch format {
+	case OutputDotenv:
+		quote = quoteDotenv
+	case OutputEnv:
+		quote = quoteShell
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected %s or %s", format, OutputDotenv, OutputEnv)
+	}
+
+	var b strings.Builder
+	for _, output := range report.Outputs() {
+		fmt.Fprintf(&b, "%s%s=%s\n", EnvOutputPrefix, strings.ToUpper(output.Name), quote(output.Value))
+	}
+	return b.String(), nil
+}
+
+// WriteEnvFile writes the rendered environment variables to the file, replacing its content
+func WriteEnvFile(path string, content string) error {
+	// #nosec G306 -- the file is consumed by other jobs of the pipeline
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("unable to write output file: %w", err)
+	}
+	return nil
+}
+
+// quoteDotenv double-quotes values with special characters, keeping each variable on a single line
+func quoteDotenv(value string) string {
+	if plainEnvValue.MatchString(value) {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`).Replace(value) + `"`
+}
+
+// quoteShell single-quotes the value so it is taken literally when the file is sourced
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderEnvFile(t *testing.T) {
+	report := NewReleaseReport(SemVer{Major: 1, Minor: 4, Release: 2, EnableReleaseCandidate: true}, "v1.3.7", nil, nil, Wording{}, nil, nil)
+
+	t.Run("Dotenv", func(t *testing.T) {
+		content, err := RenderEnvFile(report, OutputDotenv)
+		assert.NoError(t, err)
+		assert.Equal(t, "SEMVER_VERSION=1.4.0-rc.2\nSEMVER_MAJOR=1\nSEMVER_MINOR=4\nSEMVER_PATCH=0\nSEMVER_PRERELEASE=rc.2\nSEMVER_PREVIOUS_VERSION=1.3.7\nSEMVER_BUMP=minor\nSEMVER_RELEASE_NEEDED=true\n", content)
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		content, err := RenderEnvFile(report, OutputEnv)
+		assert.NoError(t, err)
+		assert.Contains(t, content, "SEMVER_VERSION='1.4.0-rc.2'\n")
+		assert.Contains(t, content, "SEMVER_RELEASE_NEEDED='true'\n")
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		_, err := RenderEnvFile(report, "yaml")
+		assert.Error(t, err)
+	})
+
+	t.Run("Write file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "semver.env")
+		assert.NoError(t, WriteEnvFile(path, "SEMVER_VERSION=1.0.0\n"))
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "SEMVER_VERSION=1.0.0\n", string(content))
+	})
+}
+
+func TestQuoteEnvValues(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantDotenv string
+		wantShell  string
+	}{
+		{value: "", wantDotenv: "", wantShell: "''"},
+		{value: "1.2.3", wantDotenv: "1.2.3", wantShell: "'1.2.3'"},
+		{value: "it's $HOME", wantDotenv: `"it's $HOME"`, wantShell: `'it'\''s $HOME'`},
+		{value: "a \"b\"\nc", wantDotenv: `"a \"b\"\nc"`, wantShell: "'a \"b\"\nc'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.wantDotenv, quoteDotenv(tt.value))
+			assert.Equal(t, tt.wantShell, quoteShell(tt.value))
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Version file types, detected from the file name when not set
+const (
+	VersionFileNPM       = "npm"       // package.json
+	VersionFileHelm      = "helm"      // Chart.yaml, version and appVersion
+	VersionFilePyproject = "pyproject" // pyproject.toml, [project] or [tool.poetry]
+	VersionFileCargo     = "cargo"     // Cargo.toml, [package] or [workspace.package]
+	VersionFileMaven     = "maven"     // pom.xml, version of the project
+	VersionFileGo        = "go"        // Version constant or variable
+)
+
+// goVersionPattern matches the Version constant or variable of a Go file
+const goVersionPattern = `(?m)^\s*(?:const\s+|var\s+)?Version\s*(?:string\s*)?=\s*"([^"\n]*)"`
+
+// VersionFile represents a file keeping the version of the project.
+// The version is located by the type, or by one of the regex, JSON path or YAML path locators.
+type VersionFile struct {
+	Path     string
+	Type     string
+	Regex    string // Replaces the first capture group, or the whole match without groups
+	JSONPath string `mapstructure:"json_path"` // e.g. $.version or packages[0].version
+	YAMLPath string `mapstructure:"yaml_path"` // e.g. image.tag
+	Template string // Written value, e.g. "v{{ .Version }}"; the plain version when empty
+}
+
+// valueSpan is the position of a version value in the file content, excluding quotes
+type valueSpan struct {
+	start int
+	end   int
+}
+
+// UpdateVersionFile writes the version to the file, preserving the rest of its content.
+// Returns false when the file already has the version.
+func UpdateVersionFile(file VersionFile, version string, dryRun bool) (bool, error) {
+	// #nosec G304 -- path comes from the user's own configuration
+	content, err := os.ReadFile(file.Path)
+	if err != nil {
+		return false, fmt.Errorf("unable to read %s: %w", file.Path, err)
+	}
+
+	updated, err := ReplaceVersion(file, content, version)
+	if err != nil {
+		return false, fmt.Errorf("unable to update %s: %w", file.Path, err)
+	}
+	if bytes.Equal(content, updated) {
+		return false, nil
+	}
+	if dryRun {
+		Info("Dry run, file not updated", map[string]interface{}{"file": file.Path, "version": version})
+		return true, nil
+	}
+
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(file.Path, updated, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("unable to write %s: %w", file.Path, err)
+	}
+	return true, nil
+}
+
+// ReplaceVersion returns the content with every located version value replaced
+func ReplaceVersion(file VersionFile, content []byte, version string) ([]byte, error) {
+	if strings.ContainsAny(version, "\"'\\\n\r") {
+		return nil, fmt.Errorf("version %q contains characters which need escaping", version)
+	}
+
+	spans, err := locateVersion(file, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(spans) == 0 {
+		return nil, errors.New("version not found")
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
+	result := append([]byte{}, content...)
+	for _, span := range spans {
+		result = append(result[:span.start], append([]byte(version), result[span.end:]...)...)
+	}
+	return result, nil
+}
+
+// VersionFileType returns the type of the file, detected from its name when not set
+func VersionFileType(file VersionFile) string {
+	if file.Type != "" {
+		return file.Type
+	}
+	name := filepath.Base(file.Path)
+	switch {
+	case name == "package.json":
+		return VersionFileNPM
+	case name == "Chart.yaml":
+		return VersionFileHelm
+	case name == "pyproject.toml":
+		return VersionFilePyproject
+	case name == "Cargo.toml":
+		return VersionFileCargo
+	case name == "pom.xml":
+		return VersionFileMaven
+	case strings.HasSuffix(name, ".go"):
+		return VersionFileGo
+	default:
+		return ""
+	}
+}
+
+// locateVersion returns the positions of the version values in the content
+func locateVersion(file VersionFile, content []byte) ([]valueSpan, error) {
+	switch {
+	case file.Regex != "":
+		return locateRegex(file.Regex, content)
+	case file.JSONPath != "":
+		return locateJSON(file.JSONPath, content)
+	case file.YAMLPath != "":
+		return locateYAML([]string{file.YAMLPath}, content)
+	}
+
+	switch fileType := VersionFileType(file); fileType {
+	case VersionFileNPM:
+		return locateJSON("version", content)
+	case VersionFileHelm:
+		return locateYAML([]string{"version", "appVersion"}, content)
+	case VersionFilePyproject:
+		return locateTOML([]string{"project", "tool.poetry"}, content), nil
+	case VersionFileCargo:
+		return locateTOML([]string{"package", "workspace.package"}, content), nil
+	case VersionFileMaven:
+		return locateMaven(content)
+	case VersionFileGo:
+		return locateRegex(goVersionPattern, content)
+	case "":
+		return nil, errors.New("unknown file type, set type, regex, json_path or yaml_path")
+	default:
+		return nil, fmt.Errorf("unknown file type %q", fileType)
+	}
+}
+
+// locateRegex returns the first capture group of every match, or the whole matches without groups
+func locateRegex(pattern string, content []byte) ([]valueSpan, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	var spans []valueSpan
+	for _, match := range re.FindAllSubmatchIndex(content, -1) {
+		if len(match) >= 4 && match[2] >= 0 {
+			spans = append(spans, valueSpan{start: match[2], end: match[3]})
+		} else {
+			spans = append(spans, valueSpan{start: match[0], end: match[1]})
+		}
+	}
+	return spans, nil
+}
+
+// parsePath splits a dotted path with optional array indexes, e.g. $.packages[0].version
+func parsePath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.ReplaceAll(strings.ReplaceAll(path, "[", "."), "]", "")
+	return strings.Split(path, ".")
+}
+
+// jsonFrame tracks the position inside a JSON object or array
+type jsonFrame struct {
+	array     bool
+	index     int
+	key       string
+	expectKey bool
+}
+
+// locateJSON returns the position of the string value at the path
+func locateJSON(path string, content []byte) ([]valueSpan, error) {
+	target := strings.Join(parsePath(path), ".")
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	var stack []jsonFrame
+
+	// current returns the path of the value about to be read
+	current := func() string {
+		parts := make([]string, 0, len(stack))
+		for _, frame := range stack {
+			if frame.array {
+				parts = append(parts, strconv.Itoa(frame.index))
+			} else {
+				parts = append(parts, frame.key)
+			}
+		}
+		return strings.Join(parts, ".")
+	}
+	// valueRead moves the innermost frame past the value just read
+	valueRead := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.array {
+			top.index++
+		} else {
+			top.expectKey = true
+		}
+	}
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		switch t := token.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				stack = append(stack, jsonFrame{expectKey: true})
+			case '[':
+				stack = append(stack, jsonFrame{array: true})
+			default:
+				stack = stack[:len(stack)-1]
+				valueRead()
+			}
+			continue
+		case string:
+			if top := len(stack) - 1; top >= 0 && !stack[top].array && stack[top].expectKey {
+				stack[top].key = t
+				stack[top].expectKey = false
+				continue
+			}
+		}
+
+		if current() == target {
+			if _, ok := token.(string); !ok {
+				return nil, fmt.Errorf("value at %s is not a string", path)
+			}
+			// Skip separators preceding the value to find its opening quote
+			start := int(offset)
+			for start < len(content) && content[start] != '"' {
+				start++
+			}
+			return []valueSpan{{start: start + 1, end: int(decoder.InputOffset()) - 1}}, nil
+		}
+		valueRead()
+	}
+}
+
+// locateYAML returns the positions of the scalar values at the paths
+func locateYAML(paths []string, content []byte) ([]valueSpan, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	lineStarts := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	var spans []valueSpan
+	for _, path := range paths {
+		node := findYAMLNode(document.Content[0], parsePath(path))
+		if node == nil {
+			continue
+		}
+		if node.Kind != yaml.ScalarNode || node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return nil, fmt.Errorf("value at %s is not a single line scalar", path)
+		}
+
+		start := columnOffset(content, lineStarts[node.Line-1], node.Column)
+		switch {
+		case node.Style&yaml.DoubleQuotedStyle != 0:
+			end := start + 1
+			for end < len(content) && content[end] != '"' {
+				if content[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			spans = append(spans, valueSpan{start: start + 1, end: end})
+		case node.Style&yaml.SingleQuotedStyle != 0:
+			end := start + 1
+			for end < len(content) && (content[end] != '\'' || (end+1 < len(content) && content[end+1] == '\'')) {
+				if content[end] == '\'' {
+					end++
+				}
+				end++
+			}
+			spans = append(spans, valueSpan{start: start + 1, end: end})
+		default:
+			spans = append(spans, valueSpan{start: start, end: start + len(node.Value)})
+		}
+	}
+	return spans, nil
+}
+
+// columnOffset returns the byte offset of the column on the line starting at lineStart.
+// yaml reports columns in characters, which differ from bytes after multi-byte characters.
+func columnOffset(content []byte, lineStart int, column int) int {
+	offset := lineStart
+	for i := 1; i < column && offset < len(content); i++ {
+		_, size := utf8.DecodeRune(content[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// findYAMLNode walks the mappings and sequences along the path
+func findYAMLNode(node *yaml.Node, path []string) *yaml.Node {
+	for _, part := range path {
+		switch node.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == part {
+					next = node.Content[i+1]
+					break
+				}
+			}
+			if next == nil {
+				return nil
+			}
+			node = next
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[index]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+var (
+	tomlTable   = regexp.MustCompile(`^\s*\[\s*([A-Za-z0-9_.\-]+)\s*\]`)
+	tomlVersion = regexp.MustCompile(`^\s*version\s*=\s*(?:"([^"\n]*)"|'([^'\n]*)')`)
+)
+
+// locateTOML returns the position of the version key in the first of the tables present
+func locateTOML(tables []string, content []byte) []valueSpan {
+	found := map[string]valueSpan{}
+	table := ""
+	offset := 0
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if match := tomlTable.FindStringSubmatch(line); match != nil {
+			table = match[1]
+		} else if match := tomlVersion.FindStringSubmatchIndex(line); match != nil {
+			if _, ok := found[table]; !ok {
+				group := 2
+				if match[2] < 0 {
+					group = 4
+				}
+				found[table] = valueSpan{start: offset + match[group], end: offset + match[group+1]}
+			}
+		}
+		offset += len(line)
+	}
+
+	for _, table := range tables {
+		if span, ok := found[table]; ok {
+			return []valueSpan{span}
+		}
+	}
+	return nil
+}
+
+// locateMaven returns the position of the project version, leaving parent and dependency versions untouched
+func locateMaven(content []byte) ([]valueSpan, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var stack []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if len(stack) != 2 || stack[0] != "project" || t.Name.Local != "version" {
+				continue
+			}
+			start := int(decoder.InputOffset())
+			next, err := decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("invalid XML: %w", err)
+			}
+			text, ok := next.(xml.CharData)
+			if !ok {
+				return nil, errors.New("project version is empty")
+			}
+			// Keep the whitespace around the version
+			leading := len(text) - len(bytes.TrimLeft(text, " \t\r\n"))
+			value := bytes.TrimSpace(text)
+			return []valueSpan{{start: start + leading, end: start + leading + len(value)}}, nil
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    VersionFile
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "package.json keeps formatting and nested versions",
+			file:    VersionFile{Path: "package.json"},
+			content: "{\n  \"name\": \"app\",\n  \"engines\": { \"version\": \"1\" },\n  \"version\":   \"0.1.0\",\n  \"dependencies\": {}\n}\n",
+			want:    "{\n  \"name\": \"app\",\n  \"engines\": { \"version\": \"1\" },\n  \"version\":   \"1.2.3\",\n  \"dependencies\": {}\n}\n",
+		},
+		{
+			name:    "JSON path with array index",
+			file:    VersionFile{Path: "manifest.json", JSONPath: "$.packages[1].version"},
+			content: `{"packages": [{"version": "0.0.1"}, {"version": "0.0.2"}]}`,
+			want:    `{"packages": [{"version": "0.0.1"}, {"version": "1.2.3"}]}`,
+		},
+		{
+			name:    "JSON path to a number",
+			file:    VersionFile{Path: "manifest.json", JSONPath: "version"},
+			content: `{"version": 1}`,
+			wantErr: true,
+		},
+		{
+			name:    "Chart.yaml version and quoted appVersion",
+			file:    VersionFile{Path: "charts/app/Chart.yaml"},
+			content: "apiVersion: v2\nname: app # the app\nversion: 0.1.0 # chart\nappVersion: \"0.1.0\"\n",
+			want:    "apiVersion: v2\nname: app # the app\nversion: 1.2.3 # chart\nappVersion: \"1.2.3\"\n",
+		},
+		{
+			name:    "YAML path",
+			file:    VersionFile{Path: "values.yaml", YAMLPath: "image.tag"},
+			content: "image:\n  repository: app\n  tag: 'v0.1.0'\n",
+			want:    "image:\n  repository: app\n  tag: '1.2.3'\n",
+		},
+		{
+			name:    "YAML path after multi-byte characters",
+			file:    VersionFile{Path: "manifest.yaml", YAMLPath: "a.b"},
+			content: "name: zürich ✓\na: {x: ü, b: 1.0.0}\n",
+			want:    "name: zürich ✓\na: {x: ü, b: 1.2.3}\n",
+		},
+		{
+			name:    "pyproject.toml project table",
+			file:    VersionFile{Path: "pyproject.toml"},
+			content: "[build-system]\nrequires = [\"hatchling\"]\n\n[project]\nname = \"app\"\nversion = \"0.1.0\"  # managed\n",
+			want:    "[build-system]\nrequires = [\"hatchling\"]\n\n[project]\nname = \"app\"\nversion = \"1.2.3\"  # managed\n",
+		},
+		{
+			name:    "pyproject.toml poetry table",
+			file:    VersionFile{Path: "pyproject.toml"},
+			content: "[tool.poetry]\nversion = '0.1.0'\n",
+			want:    "[tool.poetry]\nversion = '1.2.3'\n",
+		},
+		{
+			name:    "Cargo.toml leaves dependency versions",
+			file:    VersionFile{Path: "Cargo.toml"},
+			content: "[package]\nname = \"app\"\nversion = \"0.1.0\"\n\n[dependencies.serde]\nversion = \"1.0\"\n",
+			want:    "[package]\nname = \"app\"\nversion = \"1.2.3\"\n\n[dependencies.serde]\nversion = \"1.0\"\n",
+		},
+		{
+			name:    "pom.xml leaves parent and dependency versions",
+			file:    VersionFile{Path: "pom.xml"},
+			content: "<project>\n  <parent>\n    <version>3.0.0</version>\n  </parent>\n  <version> 0.1.0 </version>\n  <dependencies><dependency><version>2.0</version></dependency></dependencies>\n</project>\n",
+			want:    "<project>\n  <parent>\n    <version>3.0.0</version>\n  </parent>\n  <version> 1.2.3 </version>\n  <dependencies><dependency><version>2.0</version></dependency></dependencies>\n</project>\n",
+		},
+		{
+			name:    "Go version constant",
+			file:    VersionFile{Path: "internal/version/version.go"},
+			content: "package version\n\n// Version of the app\nconst Version = \"0.1.0\"\n",
+			want:    "package version\n\n// Version of the app\nconst Version = \"1.2.3\"\n",
+		},
+		{
+			name:    "Regex capture group",
+			file:    VersionFile{Path: "Dockerfile", Regex: `LABEL version="([^"]+)"`},
+			content: "FROM scratch\nLABEL version=\"0.1.0\"\n",
+			want:    "FROM scratch\nLABEL version=\"1.2.3\"\n",
+		},
+		{
+			name:    "Regex without group",
+			file:    VersionFile{Path: "VERSION", Regex: `\d+\.\d+\.\d+`},
+			content: "0.1.0\n",
+			want:    "1.2.3\n",
+		},
+		{
+			name:    "Version not found",
+			file:    VersionFile{Path: "Cargo.toml"},
+			content: "[workspace]\nmembers = []\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown file type",
+			file:    VersionFile{Path: "README.md"},
+			content: "# App\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReplaceVersion(tt.file, []byte(tt.content), "1.2.3")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestUpdateVersionFile(t *testing.T) {
+	InitLogger(false)
+
+	path := filepath.Join(t.TempDir(), "package.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version": "0.1.0"}`), 0o600))
+	file := VersionFile{Path: path}
+
+	updated, err := UpdateVersionFile(file, "1.0.0", true)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, `{"version": "0.1.0"}`, string(content), "Dry run should not write the file")
+
+	updated, err = UpdateVersionFile(file, "1.0.0", false)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	content, _ = os.ReadFile(path)
+	assert.Equal(t, `{"version": "1.0.0"}`, string(content))
+
+	updated, err = UpdateVersionFile(file, "1.0.0", false)
+	assert.NoError(t, err)
+	assert.False(t, updated, "File already has the version")
+
+	_, err = UpdateVersionFile(file, `1.0.0"`, false)
+	assert.Error(t, err)
+}
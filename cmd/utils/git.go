@@ -1,15 +1,19 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
@@ -27,6 +31,12 @@ type TagDetails struct {
 	Hash string
 }
 
+// SkippedTag represents an existing tag which is not used as the baseline
+type SkippedTag struct {
+	Name   string
+	Reason string
+}
+
 // GitRepository represents a git repository
 type GitRepository struct {
 	Handler     *git.Repository
@@ -37,8 +47,14 @@ type GitRepository struct {
 	Commits     []CommitDetails
 	Tags        []TagDetails
 	StartCommit string
+	Commit      string      // Commit to calculate the version for, HEAD when empty
+	TagVerifier TagVerifier // When set, only tags with a valid signature are listed
+	SkippedTags []SkippedTag
 }
 
+// SkipReasonNotSemver is the reason of tags skipped because they are not semantic versions (e.g. "v1", "latest")
+const SkipReasonNotSemver = "not a semantic version"
+
 // PrepareRepository prepares the git repository for use
 func PrepareRepository(repo *GitRepository) error {
 	var err error
@@ -60,11 +76,8 @@ func PrepareRepository(repo *GitRepository) error {
 			URL:           repo.Name,
 			ReferenceName: plumbing.NewBranchReferenceName(repo.Branch),
 			SingleBranch:  true,
-			Auth: &http.BasicAuth{
-				Username: os.Getenv("GITHUB_USERNAME"),
-				Password: os.Getenv("GITHUB_TOKEN"),
-			},
-			Tags: git.AllTags,
+			Auth:          RepositoryAuth(repo.Name),
+			Tags:          git.AllTags,
 		})
 
 		if err != nil {
@@ -96,23 +109,42 @@ func PrepareRepository(repo *GitRepository) error {
 	return nil
 }
 
+// IsShallow reports whether the repository is a shallow clone, missing part of the history
+func IsShallow(repo *GitRepository) bool {
+	if repo.Handler == nil {
+		return false
+	}
+	shallow, err := repo.Handler.Storer.Shallow()
+	return err == nil && len(shallow) > 0
+}
+
+// RepositoryAuth returns the credentials for the remote repository URL.
+// HTTP(S) remotes use basic auth built from GITHUB_USERNAME and GITHUB_TOKEN,
+// other transports (ssh, file) return nil to use their own defaults.
+func RepositoryAuth(remoteURL string) transport.AuthMethod {
+	if ep, err := transport.NewEndpoint(remoteURL); err == nil && ep.Protocol != "http" && ep.Protocol != "https" {
+		return nil
+	}
+	return &http.BasicAuth{
+		Username: os.Getenv("GITHUB_USERNAME"),
+		Password: os.Getenv("GITHUB_TOKEN"),
+	}
+}
+
 // ListCommits lists all commits in the repository
 func ListCommits(repo *GitRepository) ([]CommitDetails, error) {
-	var ref *plumbing.Reference
-	var err error
-
 	// Check if Handler is nil to avoid panic
 	if repo.Handler == nil {
 		Debug("Repository handler is nil, skipping commit listing", nil)
 		return repo.Commits, nil
 	}
 
-	ref, err = repo.Handler.Head()
+	from, err := resolveHead(repo)
 	if err != nil {
 		return []CommitDetails{}, err
 	}
 
-	commitsList, err := repo.Handler.Log(&git.LogOptions{From: ref.Hash()})
+	commitsList, err := repo.Handler.Log(&git.LogOptions{From: from})
 	if err != nil {
 		return []CommitDetails{}, err
 	}
@@ -162,11 +194,152 @@ func ListCommits(repo *GitRepository) ([]CommitDetails, error) {
 	return repo.Commits, err
 }
 
-// ListExistingTags lists all tags in the repository
+// ResolveRevision returns the full hash of the commit a revision (branch, tag, short hash, HEAD~2, ...) points to
+func ResolveRevision(repo *GitRepository, revision string) (string, error) {
+	if repo.Handler == nil {
+		return "", fmt.Errorf("repository is not prepared")
+	}
+	hash, err := repo.Handler.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %s: %w", revision, err)
+	}
+	return hash.String(), nil
+}
+
+// CommitFileReader returns a reader of the files in the tree of the commit, the one the history is listed from
+// when empty. Paths are relative to the repository root, files missing from the tree are fs.ErrNotExist errors.
+func CommitFileReader(repo *GitRepository, commit string) (FileReader, error) {
+	if repo.Handler == nil {
+		return nil, fmt.Errorf("repository is not prepared")
+	}
+	hash := plumbing.NewHash(commit)
+	if commit == "" {
+		var err error
+		if hash, err = resolveHead(repo); err != nil {
+			return nil, err
+		}
+	}
+	c, err := repo.Handler.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read commit %s: %w", hash, err)
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	return treeFileReader(tree), nil
+}
+
+// treeFileReader returns a reader of the files of the tree, files missing from it are fs.ErrNotExist errors
+func treeFileReader(tree *object.Tree) FileReader {
+	return func(path string) ([]byte, error) {
+		file, err := tree.File(filepath.ToSlash(filepath.Clean(path)))
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := file.Contents()
+		return []byte(content), err
+	}
+}
+
+// CommitRange returns the commits reachable from `to` but not from `from`, oldest first, like git log from..to
+func CommitRange(repo *GitRepository, from string, to string) ([]CommitDetails, error) {
+	fromHash, err := ResolveRevision(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toHash, err := ResolveRevision(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[plumbing.Hash]bool{}
+	ancestors, err := repo.Handler.Log(&git.LogOptions{From: plumbing.NewHash(fromHash)})
+	if err != nil {
+		return nil, err
+	}
+	if err := ancestors.ForEach(func(c *object.Commit) error {
+		excluded[c.Hash] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	commits, err := repo.Handler.Log(&git.LogOptions{From: plumbing.NewHash(toHash)})
+	if err != nil {
+		return nil, err
+	}
+	var result []CommitDetails
+	if err := commits.ForEach(func(c *object.Commit) error {
+		if !excluded[c.Hash] {
+			result = append(result, CommitDetails{
+				Hash:      c.Hash.String(),
+				Author:    c.Author.String(),
+				Message:   c.Message,
+				Timestamp: c.Author.When,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Unix() < result[j].Timestamp.Unix()
+	})
+	return result, nil
+}
+
+// ResolveHead returns the hash of the commit the history is listed from, which the version is calculated for
+func ResolveHead(repo *GitRepository) (string, error) {
+	if repo.Handler == nil {
+		return "", fmt.Errorf("repository is not prepared")
+	}
+	hash, err := resolveHead(repo)
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// resolveHead returns the commit the history is listed from: the configured commit when it exists
+// in the repository, the branch when a local checkout has a detached HEAD, HEAD otherwise
+func resolveHead(repo *GitRepository) (plumbing.Hash, error) {
+	if repo.Commit != "" {
+		hash := plumbing.NewHash(repo.Commit)
+		if _, err := repo.Handler.CommitObject(hash); err == nil {
+			return hash, nil
+		}
+		Info("Commit not found in repository, using HEAD", map[string]interface{}{"commit": repo.Commit})
+	}
+
+	head, err := repo.Handler.Head()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if repo.UseLocal && repo.Branch != "" && !head.Name().IsBranch() {
+		for _, name := range []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(repo.Branch),
+			plumbing.NewRemoteReferenceName("origin", repo.Branch),
+		} {
+			if ref, err := repo.Handler.Reference(name, true); err == nil {
+				Debug("Detached HEAD, using branch", map[string]interface{}{"ref": name.String()})
+				return ref.Hash(), nil
+			}
+		}
+		Debug("Branch not found, using detached HEAD", map[string]interface{}{"branch": repo.Branch})
+	}
+	return head.Hash(), nil
+}
+
 // ListExistingTags lists all tags in the repository.
 // Tags that don't parse as proper semver (rolling tags like "v1" or "latest")
 // are skipped so they can't out-rank real semver tags pointing to the same
-// commit during latest-tag selection.
+// commit during latest-tag selection. With a TagVerifier set, lightweight
+// tags and tags without a valid signature are skipped as well.
 func ListExistingTags(repo *GitRepository, tagPrefixes []string) {
 	Debug("Listing existing tags", nil)
 
@@ -186,6 +359,7 @@ func ListExistingTags(repo *GitRepository, tagPrefixes []string) {
 
 		if !IsParseableSemverTag(tagName, tagPrefixes) {
 			Debug("Skipping non-semver tag", map[string]interface{}{"tag": tagName})
+			repo.SkippedTags = append(repo.SkippedTags, SkippedTag{Name: tagName, Reason: SkipReasonNotSemver})
 			return nil
 		}
 
@@ -195,6 +369,22 @@ func ListExistingTags(repo *GitRepository, tagPrefixes []string) {
 			commitHash = tagObj.Target.String()
 		}
 
+		if repo.TagVerifier != nil {
+			if tagObj == nil {
+				Info("Skipping unsigned lightweight tag", map[string]interface{}{"tag": tagName})
+				repo.SkippedTags = append(repo.SkippedTags, SkippedTag{Name: tagName, Reason: "unsigned lightweight tag"})
+				return nil
+			}
+			if err := repo.TagVerifier(tagObj); err != nil {
+				Info("Skipping tag without valid signature", map[string]interface{}{
+					"tag":   tagName,
+					"error": err.Error(),
+				})
+				repo.SkippedTags = append(repo.SkippedTags, SkippedTag{Name: tagName, Reason: err.Error()})
+				return nil
+			}
+		}
+
 		repo.Tags = append(repo.Tags, TagDetails{
 			Name: tagName,
 			Hash: commitHash,
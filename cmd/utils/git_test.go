@@ -1,13 +1,64 @@
 package utils
 
 import (
+	"io/fs"
 	"os"
 	"testing"
 	"time"
 
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 )
 
+// initTestRepository creates a temporary git repository with one commit per message
+func initTestRepository(t *testing.T, messages ...string) *GitRepository {
+	t.Helper()
+
+	dir := t.TempDir()
+	handler, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+
+	cfg, err := handler.Config()
+	if err != nil {
+		t.Fatalf("Failed to read repository config: %v", err)
+	}
+	cfg.User.Name = "Test Author"
+	cfg.User.Email = "test@example.com"
+	if err := handler.SetConfig(cfg); err != nil {
+		t.Fatalf("Failed to write repository config: %v", err)
+	}
+
+	worktree, err := handler.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, message := range messages {
+		if err := os.WriteFile(dir+"/file.txt", []byte(message), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := worktree.Add("file.txt"); err != nil {
+			t.Fatalf("Failed to stage file: %v", err)
+		}
+		if _, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  "Test Author",
+				Email: "test@example.com",
+				When:  start.Add(time.Duration(i) * time.Hour),
+			},
+		}); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	return &GitRepository{Handler: handler, LocalPath: dir}
+}
+
 func TestPrepareRepository(t *testing.T) {
 	// Initialize logger
 	InitLogger(true)
@@ -148,3 +199,74 @@ func TestListExistingTags(t *testing.T) {
 		assert.Empty(t, repo.Tags, "Should have no tags after calling with nil Handler")
 	})
 }
+
+func TestListCommitsDetachedHead(t *testing.T) {
+	InitLogger(false)
+
+	repo := initTestRepository(t, "Initial commit", "Update readme", "Update docs")
+	repo.UseLocal = true
+	head, _ := repo.Handler.Head()
+	commits, _ := ListCommits(repo)
+
+	// Detach HEAD at the first commit, as CI checkouts do
+	assert.NoError(t, repo.Handler.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, plumbing.NewHash(commits[0].Hash))))
+
+	t.Run("Detached HEAD without branch", func(t *testing.T) {
+		repo.Branch = ""
+		commits, err := ListCommits(repo)
+		assert.NoError(t, err)
+		assert.Len(t, commits, 1)
+	})
+
+	t.Run("Detached HEAD uses branch", func(t *testing.T) {
+		repo.Branch = head.Name().Short()
+		commits, err := ListCommits(repo)
+		assert.NoError(t, err)
+		assert.Len(t, commits, 3)
+	})
+
+	t.Run("Commit takes precedence", func(t *testing.T) {
+		repo.Commit = commits[1].Hash
+		commits, err := ListCommits(repo)
+		assert.NoError(t, err)
+		assert.Len(t, commits, 2)
+	})
+
+	t.Run("Unknown commit falls back to HEAD", func(t *testing.T) {
+		repo.Branch = ""
+		repo.Commit = "0123456789012345678901234567890123456789"
+		commits, err := ListCommits(repo)
+		assert.NoError(t, err)
+		assert.Len(t, commits, 1)
+	})
+}
+
+func TestCommitFileReader(t *testing.T) {
+	InitLogger(false)
+
+	repo := initTestRepository(t, "Initial commit", "Update docs")
+	commits, err := ListCommits(repo)
+	assert.NoError(t, err)
+
+	read, err := CommitFileReader(repo, "")
+	assert.NoError(t, err)
+	content, err := read("file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "Update docs", string(content))
+
+	read, err = CommitFileReader(repo, commits[0].Hash)
+	assert.NoError(t, err)
+	content, err = read("./file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "Initial commit", string(content))
+
+	_, err = read("missing.yaml")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = read("../file.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = CommitFileReader(repo, "0123456789012345678901234567890123456789")
+	assert.Error(t, err)
+	_, err = CommitFileReader(&GitRepository{}, "")
+	assert.Error(t, err)
+}
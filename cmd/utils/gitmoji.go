@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GitmojiWording classifies commits by their gitmoji (https://gitmoji.dev), written as emoji or :shortcode:
+type GitmojiWording struct {
+	Enabled bool
+	Table   map[string]string // Emoji or :shortcode: to level (patch, release, minor, major or none), applied over the defaults
+}
+
+// gitmoji is an emoji of the gitmoji list with its shortcode and default level
+type gitmoji struct {
+	Emoji     string
+	Shortcode string
+	Level     Bump
+}
+
+// gitmojis lists the gitmoji with the semver level they are documented with, none for the ones not affecting releases.
+// Emoji are stored without the U+FE0F variation selector, which is stripped from messages before matching.
+var gitmojis = []gitmoji{
+	{"🎨", ":art:", BumpNone},
+	{"⚡", ":zap:", BumpPatch},
+	{"🔥", ":fire:", BumpNone},
+	{"🐛", ":bug:", BumpPatch},
+	{"🚑", ":ambulance:", BumpPatch},
+	{"✨", ":sparkles:", BumpMinor},
+	{"📝", ":memo:", BumpNone},
+	{"🚀", ":rocket:", BumpNone},
+	{"💄", ":lipstick:", BumpPatch},
+	{"🎉", ":tada:", BumpNone},
+	{"✅", ":white_check_mark:", BumpNone},
+	{"🔒", ":lock:", BumpPatch},
+	{"🔐", ":closed_lock_with_key:", BumpNone},
+	{"🔖", ":bookmark:", BumpNone},
+	{"🚨", ":rotating_light:", BumpNone},
+	{"🚧", ":construction:", BumpNone},
+	{"💚", ":green_heart:", BumpNone},
+	{"⬇", ":arrow_down:", BumpPatch},
+	{"⬆", ":arrow_up:", BumpPatch},
+	{"📌", ":pushpin:", BumpPatch},
+	{"👷", ":construction_worker:", BumpNone},
+	{"📈", ":chart_with_upwards_trend:", BumpPatch},
+	{"♻", ":recycle:", BumpNone},
+	{"➕", ":heavy_plus_sign:", BumpPatch},
+	{"➖", ":heavy_minus_sign:", BumpPatch},
+	{"🔧", ":wrench:", BumpPatch},
+	{"🔨", ":hammer:", BumpNone},
+	{"🌐", ":globe_with_meridians:", BumpPatch},
+	{"✏", ":pencil2:", BumpPatch},
+	{"💩", ":poop:", BumpNone},
+	{"⏪", ":rewind:", BumpPatch},
+	{"🔀", ":twisted_rightwards_arrows:", BumpNone},
+	{"📦", ":package:", BumpPatch},
+	{"👽", ":alien:", BumpPatch},
+	{"🚚", ":truck:", BumpNone},
+	{"📄", ":page_facing_up:", BumpNone},
+	{"💥", ":boom:", BumpMajor},
+	{"🍱", ":bento:", BumpPatch},
+	{"♿", ":wheelchair:", BumpPatch},
+	{"💡", ":bulb:", BumpNone},
+	{"🍻", ":beers:", BumpNone},
+	{"💬", ":speech_balloon:", BumpPatch},
+	{"🗃", ":card_file_box:", BumpPatch},
+	{"🔊", ":loud_sound:", BumpNone},
+	{"🔇", ":mute:", BumpNone},
+	{"👥", ":busts_in_silhouette:", BumpNone},
+	{"🚸", ":children_crossing:", BumpPatch},
+	{"🏗", ":building_construction:", BumpNone},
+	{"📱", ":iphone:", BumpPatch},
+	{"🤡", ":clown_face:", BumpNone},
+	{"🥚", ":egg:", BumpPatch},
+	{"🙈", ":see_no_evil:", BumpNone},
+	{"📸", ":camera_flash:", BumpNone},
+	{"⚗", ":alembic:", BumpPatch},
+	{"🔍", ":mag:", BumpPatch},
+	{"🏷", ":label:", BumpPatch},
+	{"🌱", ":seedling:", BumpNone},
+	{"🚩", ":triangular_flag_on_post:", BumpPatch},
+	{"🥅", ":goal_net:", BumpPatch},
+	{"💫", ":dizzy:", BumpPatch},
+	{"🗑", ":wastebasket:", BumpPatch},
+	{"🛂", ":passport_control:", BumpPatch},
+	{"🩹", ":adhesive_bandage:", BumpPatch},
+	{"🧐", ":monocle_face:", BumpNone},
+	{"⚰", ":coffin:", BumpNone},
+	{"🧪", ":test_tube:", BumpNone},
+	{"👔", ":necktie:", BumpPatch},
+	{"🩺", ":stethoscope:", BumpNone},
+	{"🧱", ":bricks:", BumpNone},
+	{"🧑‍💻", ":technologist:", BumpNone},
+	{"💸", ":money_with_wings:", BumpNone},
+	{"🧵", ":thread:", BumpNone},
+	{"🦺", ":safety_vest:", BumpNone},
+}
+
+// gitmojiShortcode matches :shortcode: references in commit messages
+var gitmojiShortcode = regexp.MustCompile(`:[a-z0-9_+\-]+:`)
+
+// gitmojiShortcodeKey matches table keys which are a :shortcode:
+var gitmojiShortcodeKey = regexp.MustCompile(`(?i)^:[a-z0-9_+\-]+:$`)
+
+// normalizeGitmoji strips the variation selector some clients append to emoji, e.g. ⚡️
+func normalizeGitmoji(s string) string {
+	return strings.ReplaceAll(s, "\ufe0f", "")
+}
+
+// IsGitmojiShortcode reports whether the table key is a :shortcode: rather than an emoji
+func IsGitmojiShortcode(key string) bool {
+	return gitmojiShortcodeKey.MatchString(key)
+}
+
+// levels returns the level of every emoji and shortcode, with the table applied over the defaults.
+// A table entry for a known gitmoji applies to both its emoji and its shortcode.
+func (g GitmojiWording) levels() map[string]Bump {
+	levels := make(map[string]Bump, 2*len(gitmojis)+len(g.Table))
+	aliases := make(map[string]string, 2*len(gitmojis))
+	for _, gm := range gitmojis {
+		levels[gm.Emoji] = gm.Level
+		levels[gm.Shortcode] = gm.Level
+		aliases[gm.Emoji] = gm.Shortcode
+		aliases[gm.Shortcode] = gm.Emoji
+	}
+
+	// Sorted, so the outcome does not depend on the map order when an emoji and its shortcode are both configured
+	keys := make([]string, 0, len(g.Table))
+	for key := range g.Table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		level, err := ParseBump(g.Table[key])
+		if err != nil {
+			Error("Ignoring gitmoji with an unknown level", map[string]interface{}{
+				"gitmoji": key,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		key = normalizeGitmoji(strings.TrimSpace(key))
+		if IsGitmojiShortcode(key) {
+			key = strings.ToLower(key)
+		}
+		levels[key] = level
+		if alias, ok := aliases[key]; ok {
+			levels[alias] = level
+		}
+	}
+	return levels
+}
+
+// ClassifyGitmoji returns the highest level of the gitmoji in the message and the gitmoji of that level,
+// in the order they appear. Unknown shortcodes and gitmoji of no level are ignored.
+func ClassifyGitmoji(message string, g GitmojiWording) (Bump, []string) {
+	message = normalizeGitmoji(message)
+	type occurrence struct {
+		index   int
+		gitmoji string
+		level   Bump
+	}
+	var found []occurrence
+
+	levels := g.levels()
+	lower := strings.ToLower(message)
+	for _, loc := range gitmojiShortcode.FindAllStringIndex(lower, -1) {
+		shortcode := lower[loc[0]:loc[1]]
+		if level, ok := levels[shortcode]; ok && level != BumpNone {
+			found = append(found, occurrence{index: loc[0], gitmoji: shortcode, level: level})
+		}
+	}
+	for key, level := range levels {
+		if level == BumpNone || IsGitmojiShortcode(key) {
+			continue
+		}
+		for offset := 0; ; {
+			index := strings.Index(message[offset:], key)
+			if index == -1 {
+				break
+			}
+			found = append(found, occurrence{index: offset + index, gitmoji: key, level: level})
+			offset += index + len(key)
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].index < found[j].index })
+	bump := BumpNone
+	var matched []string
+	for _, o := range found {
+		switch {
+		case o.level > bump:
+			bump, matched = o.level, []string{o.gitmoji}
+		case o.level == bump:
+			matched = append(matched, o.gitmoji)
+		}
+	}
+	return bump, matched
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyGitmoji(t *testing.T) {
+	InitLogger(false)
+
+	tests := []struct {
+		name    string
+		message string
+		table   map[string]string
+		want    Bump
+		found   []string
+	}{
+		{name: "Emoji", message: "✨ Add login", want: BumpMinor, found: []string{"✨"}},
+		{name: "Shortcode", message: ":boom: Drop the v1 API", want: BumpMajor, found: []string{":boom:"}},
+		{name: "Uppercase shortcode", message: ":BUG: crash", want: BumpPatch, found: []string{":bug:"}},
+		{name: "Variation selector", message: "⚡️ Faster startup", want: BumpPatch, found: []string{"⚡"}},
+		{name: "Highest level wins", message: "🐛 fix crash ✨ and :bug: again", want: BumpMinor, found: []string{"✨"}},
+		{name: "Same level in order", message: "🐛 :ambulance: hotfix", want: BumpPatch, found: []string{"🐛", ":ambulance:"}},
+		{name: "Emoji without level", message: "📝 Update the readme", want: BumpNone},
+		{name: "Unknown shortcode", message: ":unicorn: time 10:30:", want: BumpNone},
+		{name: "No gitmoji", message: "Add login", want: BumpNone},
+		{
+			name:    "Table applies to the emoji of a shortcode",
+			message: "♻️ Extract the parser",
+			table:   map[string]string{":recycle:": "patch"},
+			want:    BumpPatch,
+			found:   []string{"♻"},
+		},
+		{
+			name:    "Table applies to the shortcode of an emoji",
+			message: ":sparkles: Add login",
+			table:   map[string]string{"✨": "none", ":lipstick:": "minor"},
+			want:    BumpNone,
+		},
+		{
+			name:    "Custom emoji",
+			message: "🦄 Add magic",
+			table:   map[string]string{"🦄": "minor", ":bug:": "unknown"},
+			want:    BumpMinor,
+			found:   []string{"🦄"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := ClassifyGitmoji(tt.message, GitmojiWording{Enabled: true, Table: tt.table})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestGitmojiTable(t *testing.T) {
+	shortcodes := make(map[string]bool)
+	emoji := make(map[string]bool)
+	for _, gm := range gitmojis {
+		assert.True(t, IsGitmojiShortcode(gm.Shortcode), gm.Shortcode)
+		assert.False(t, shortcodes[gm.Shortcode], "duplicate shortcode %s", gm.Shortcode)
+		assert.False(t, emoji[gm.Emoji], "duplicate emoji %s", gm.Emoji)
+		assert.Equal(t, gm.Emoji, normalizeGitmoji(gm.Emoji), "%s is stored without variation selector", gm.Shortcode)
+		shortcodes[gm.Shortcode] = true
+		emoji[gm.Emoji] = true
+	}
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// History output formats
+const (
+	HistoryTable = "table"
+	HistoryCSV   = "csv"
+	HistoryJSON  = "json"
+)
+
+// VersionChange describes a commit at which the calculated version changes
+type VersionChange struct {
+	Version   SemVer
+	Previous  SemVer // Version before the commit
+	Hash      string
+	Timestamp time.Time
+	Author    string
+	Subject   string
+	Bump      Bump
+	Keywords  []string // Configured keywords that triggered the bump
+	Tag       string   // Existing tag the version was taken from, if any
+}
+
+// VersionHistory replays the calculation over the commits, oldest first, returning every commit
+// at which the version changes. With respectExisting a tagged commit takes the version of its tag,
+// exactly as CalculateSemver does when the tag is the latest one.
+func VersionHistory(
+	commits []CommitDetails,
+	tags []TagDetails,
+	wording Wording,
+	blacklist []string,
+	rulesAt RulesAt,
+	initialSemver SemVer,
+	respectExisting bool,
+	strictMode bool,
+	tagPrefixes []string,
+) []VersionChange {
+	tagged := make(map[string]string)
+	if respectExisting {
+		for _, tag := range tags {
+			if _, ok := tagged[tag.Hash]; !ok {
+				tagged[tag.Hash] = tag.Name
+			}
+		}
+	}
+
+	var changes []VersionChange
+	semver := initialSemver
+	for _, commit := range commits {
+		commitWording, commitBlacklist := rulesAt.Of(commit.Hash, wording, blacklist)
+		result := LintMessage(commit.Message, commitWording, commitBlacklist)
+		change := VersionChange{
+			Hash:      commit.Hash,
+			Timestamp: commit.Timestamp,
+			Author:    commit.Author,
+			Subject:   result.Subject,
+			Bump:      result.Bump,
+			Keywords:  result.Keywords,
+		}
+
+		previous := semver
+		if tag, ok := tagged[commit.Hash]; ok {
+			semver = ParseExistingSemver(tag, semver, tagPrefixes)
+			change.Tag = tag
+		} else {
+			semver = nextSemver(semver, commit.Message, result.Bump, strictMode)
+		}
+		if semver == previous {
+			continue
+		}
+		change.Version = semver
+		change.Previous = previous
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// historyRow is the representation of a version change shared by all output formats
+type historyRow struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+	Author  string `json:"author"`
+	Bump    string `json:"bump"`
+	Keyword string `json:"keyword"`
+	Tag     string `json:"tag"`
+	Subject string `json:"subject"`
+}
+
+// RenderHistory renders the version changes as an aligned table, CSV or a JSON array
+func RenderHistory(changes []VersionChange, format string) (string, error) {
+	rows := make([]historyRow, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, historyRow{
+			Version: FormatSemver(change.Version),
+			Commit:  change.Hash,
+			Date:    change.Timestamp.UTC().Format(time.RFC3339),
+			Author:  change.Author,
+			Bump:    change.Bump.String(),
+			Keyword: strings.Join(change.Keywords, ", "),
+			Tag:     change.Tag,
+			Subject: change.Subject,
+		})
+	}
+
+	var b bytes.Buffer
+	switch format {
+	case HistoryTable:
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tCOMMIT\tDATE\tAUTHOR\tBUMP\tKEYWORD\tTAG\tSUBJECT")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", row.Version, shortHash(row.Commit), row.Date, row.Author, row.Bump,
+				orDash(row.Keyword), orDash(row.Tag), row.Subject)
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+	case HistoryCSV:
+		w := csv.NewWriter(&b)
+		_ = w.Write([]string{"version", "commit", "date", "author", "bump", "keyword", "tag", "subject"})
+		for _, row := range rows {
+			_ = w.Write([]string{row.Version, row.Commit, row.Date, row.Author, row.Bump, row.Keyword, row.Tag, row.Subject})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return "", err
+		}
+	case HistoryJSON:
+		encoder := json.NewEncoder(&b)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rows); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unknown history format %q, expected %s, %s or %s", format, HistoryTable, HistoryCSV, HistoryJSON)
+	}
+	return b.String(), nil
+}
+
+// orDash returns a dash for empty table cells
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
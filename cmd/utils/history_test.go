@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionHistory(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{Patch: []string{"fix"}, Minor: []string{"feat"}, Major: []string{"breaking"}, Release: []string{"rc"}}
+	blacklist := []string{"Merge branch"}
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var commits []CommitDetails
+	for i, message := range []string{"Initial commit", "fix: typo", "feat: login", "Merge branch 'feat'", "Update docs", "rc: first", "breaking: new API", "fix: crash"} {
+		commits = append(commits, CommitDetails{
+			Hash:      strings.Repeat(string(rune('a'+i)), 40),
+			Author:    "Test Author",
+			Message:   message,
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	tags := []TagDetails{{Name: "v1.5.0", Hash: commits[4].Hash}}
+
+	tests := []struct {
+		name            string
+		respectExisting bool
+		strict          bool
+		wantVersions    []string
+	}{
+		{
+			name:         "Strict mode only changes on keywords",
+			strict:       true,
+			wantVersions: []string{"0.0.1", "0.1.1", "0.1.1-rc.1", "1.0.1", "1.0.2"},
+		},
+		{
+			name:         "Every commit is a patch outside of strict mode",
+			wantVersions: []string{"0.0.1", "0.0.3", "0.1.1", "0.1.2", "0.1.3", "0.1.1-rc.1", "1.0.1", "1.0.3"},
+		},
+		{
+			name:            "Tags reset the version",
+			respectExisting: true,
+			strict:          true,
+			wantVersions:    []string{"0.0.1", "0.1.1", "1.5.0", "1.5.1-rc.1", "2.0.1", "2.0.2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := VersionHistory(commits, tags, wording, blacklist, nil, SemVer{}, tt.respectExisting, tt.strict, nil)
+			var versions []string
+			for _, change := range changes {
+				versions = append(versions, FormatSemver(change.Version))
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+
+			// Every change matches the calculation up to its commit
+			for _, change := range changes {
+				for i, commit := range commits {
+					if commit.Hash == change.Hash {
+						assert.Equal(t, CalculateSemver(commits[:i+1], tags, wording, blacklist, nil, SemVer{}, tt.respectExisting, tt.strict, nil), change.Version, change.Subject)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("Change details", func(t *testing.T) {
+		changes := VersionHistory(commits, tags, wording, blacklist, nil, SemVer{}, true, true, nil)
+		assert.Equal(t, commits[2].Hash, changes[1].Hash)
+		assert.Equal(t, BumpMinor, changes[1].Bump)
+		assert.Equal(t, []string{"feat"}, changes[1].Keywords)
+		assert.Equal(t, "v1.5.0", changes[2].Tag)
+		assert.Equal(t, changes[1].Version, changes[2].Previous)
+		assert.Equal(t, commits[2].Timestamp, changes[1].Timestamp)
+	})
+}
+
+func TestRenderHistory(t *testing.T) {
+	changes := []VersionChange{
+		{
+			Version:   SemVer{Major: 1, Minor: 1, Patch: 1},
+			Hash:      "0123456789abcdef0123456789abcdef01234567",
+			Timestamp: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+			Author:    "Test Author",
+			Subject:   "feat: login, logout",
+			Bump:      BumpMinor,
+			Keywords:  []string{"feat"},
+		},
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		output, err := RenderHistory(changes, HistoryTable)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		assert.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "VERSION"))
+		assert.Equal(t, strings.Fields("1.1.1 0123456 2021-01-01T12:00:00Z Test Author minor feat - feat: login, logout"), strings.Fields(lines[1]))
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		output, err := RenderHistory(changes, HistoryCSV)
+		assert.NoError(t, err)
+		assert.Equal(t, "version,commit,date,author,bump,keyword,tag,subject\n"+
+			"1.1.1,0123456789abcdef0123456789abcdef01234567,2021-01-01T12:00:00Z,Test Author,minor,feat,,\"feat: login, logout\"\n", output)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		output, err := RenderHistory(changes, HistoryJSON)
+		assert.NoError(t, err)
+		var rows []map[string]string
+		assert.NoError(t, json.Unmarshal([]byte(output), &rows))
+		assert.Equal(t, "1.1.1", rows[0]["version"])
+		assert.Equal(t, "feat", rows[0]["keyword"])
+
+		output, err = RenderHistory(nil, HistoryJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", output)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		_, err := RenderHistory(changes, "xml")
+		assert.Error(t, err)
+	})
+}
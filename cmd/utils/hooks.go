@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// HookMarker identifies the hooks written by the tool, so only those are replaced or removed
+const HookMarker = "# Installed by semver-generator"
+
+// ErrForeignHook is returned when a hook not written by the tool is in the way
+var ErrForeignHook = errors.New("hook exists and was not installed by semver-generator")
+
+// HooksDir returns the hooks directory of the repository, respecting core.hooksPath
+// from the repository, global and system configuration
+func HooksDir(repo *GitRepository) (string, error) {
+	if repo.Handler == nil {
+		return "", fmt.Errorf("repository is not prepared")
+	}
+
+	if path := configuredHooksPath(repo); path != "" {
+		if strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			path = filepath.Join(home, path[2:])
+		}
+		if !filepath.IsAbs(path) {
+			// Relative paths are relative to the working tree, where hooks run
+			worktree, err := repo.Handler.Worktree()
+			if err != nil {
+				return "", err
+			}
+			path = filepath.Join(worktree.Filesystem.Root(), path)
+		}
+		return filepath.Abs(path)
+	}
+
+	storage, ok := repo.Handler.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", errors.New("repository is not stored on disk")
+	}
+	return filepath.Abs(filepath.Join(storage.Filesystem().Root(), "hooks"))
+}
+
+// configuredHooksPath returns core.hooksPath of the most specific configuration setting it
+func configuredHooksPath(repo *GitRepository) string {
+	var configs []*config.Config
+	if local, err := repo.Handler.Config(); err == nil {
+		configs = append(configs, local)
+	}
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		if cfg, err := config.LoadConfig(scope); err == nil {
+			configs = append(configs, cfg)
+		}
+	}
+
+	for _, cfg := range configs {
+		if cfg.Raw == nil {
+			continue
+		}
+		if path := cfg.Raw.Section("core").Option("hooksPath"); path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// InstallHook writes the executable hook script. A hook installed by the tool is replaced,
+// any other existing hook is only overwritten with force.
+func InstallHook(dir string, name string, script string, force bool) error {
+	path := filepath.Join(dir, name)
+	if !force {
+		// #nosec G304 -- path within the repository hooks directory
+		if content, err := os.ReadFile(path); err == nil && !bytes.Contains(content, []byte(HookMarker)) {
+			return fmt.Errorf("%w: %s", ErrForeignHook, path)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create hooks directory: %w", err)
+	}
+	// #nosec G306 -- hooks have to be executable
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return fmt.Errorf("unable to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0o755)
+}
+
+// UninstallHook removes the hook when it was installed by the tool.
+// Returns false when there is no such hook.
+func UninstallHook(dir string, name string) (bool, error) {
+	path := filepath.Join(dir, name)
+	// #nosec G304 -- path within the repository hooks directory
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Contains(content, []byte(HookMarker)) {
+		return false, fmt.Errorf("%w: %s", ErrForeignHook, path)
+	}
+	return true, os.Remove(path)
+}
+
+// Hook names managed by the tool
+const (
+	HookCommitMsg = "commit-msg"
+	HookPrePush   = "pre-push"
+)
+
+// HookOptions configures the generated hook scripts
+type HookOptions struct {
+	Executable string // Path of the semver-generator binary
+	ConfigFile string // Configuration file, relative paths resolve against the root of the working tree
+	Strict     bool   // Reject commit messages matching no level
+}
+
+// HookScripts renders the scripts of the managed hooks, keyed by the hook name.
+// Hooks are skipped when the binary is no longer available.
+func HookScripts(opts HookOptions) map[string]string {
+	header := fmt.Sprintf("#!/bin/sh\n%s, remove with: semver-generator hooks uninstall\n"+
+		"SEMVER_GEN=${SEMVER_GEN:-%s}\n"+
+		"command -v \"$SEMVER_GEN\" >/dev/null 2>&1 || exit 0\n", HookMarker, quoteShell(opts.Executable))
+
+	var lintFlags string
+	if opts.Strict {
+		lintFlags = " --strict"
+	}
+	commitMsg := header + fmt.Sprintf("exec \"$SEMVER_GEN\" lint -c %s%s \"$1\"\n", quoteShell(opts.ConfigFile), lintFlags)
+
+	// Only warns, the push is never blocked
+	prePush := header + fmt.Sprintf(`zero=0000000000000000000000000000000000000000
+while read -r local_ref local_sha remote_ref remote_sha; do
+	case "$local_ref" in
+	refs/tags/*)
+		[ "$local_sha" = "$zero" ] && continue
+		tag="${local_ref#refs/tags/}"
+		if ! "$SEMVER_GEN" verify -l -c %s --tag "$tag" >&2; then
+			echo "warning: tag $tag disagrees with the calculated version" >&2
+		fi
+		;;
+	esac
+done
+exit 0
+`, quoteShell(opts.ConfigFile))
+
+	return map[string]string{
+		HookCommitMsg: commitMsg,
+		HookPrePush:   prePush,
+	}
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// isolateGitConfig keeps the global git configuration of the user out of the tests
+func isolateGitConfig(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+}
+
+func TestHooksDir(t *testing.T) {
+	InitLogger(false)
+	isolateGitConfig(t)
+
+	tests := []struct {
+		name      string
+		hooksPath string
+		want      func(dir string) string
+	}{
+		{
+			name: "Default hooks directory",
+			want: func(dir string) string { return filepath.Join(dir, ".git", "hooks") },
+		},
+		{
+			name:      "Relative core.hooksPath",
+			hooksPath: ".githooks",
+			want:      func(dir string) string { return filepath.Join(dir, ".githooks") },
+		},
+		{
+			name:      "Absolute core.hooksPath",
+			hooksPath: "/opt/hooks",
+			want:      func(dir string) string { return "/opt/hooks" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := initTestRepository(t, "Initial commit")
+			if tt.hooksPath != "" {
+				cfg, _ := repo.Handler.Config()
+				cfg.Raw.Section("core").SetOption("hooksPath", tt.hooksPath)
+				assert.NoError(t, repo.Handler.SetConfig(cfg))
+			}
+			got, err := HooksDir(repo)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want(repo.LocalPath), got)
+		})
+	}
+
+	t.Run("Global core.hooksPath", func(t *testing.T) {
+		home, _ := os.UserHomeDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[core]\n\thooksPath = ~/hooks\n"), 0o600))
+		defer os.Remove(filepath.Join(home, ".gitconfig"))
+
+		repo := initTestRepository(t, "Initial commit")
+		got, err := HooksDir(repo)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, "hooks"), got)
+	})
+
+	t.Run("Repository not prepared", func(t *testing.T) {
+		_, err := HooksDir(&GitRepository{})
+		assert.Error(t, err)
+	})
+}
+
+func TestInstallHook(t *testing.T) {
+	scripts := HookScripts(HookOptions{Executable: "/usr/local/bin/semver-gen", ConfigFile: "semver.yaml"})
+
+	t.Run("Install, replace and uninstall", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "hooks")
+		assert.NoError(t, InstallHook(dir, HookCommitMsg, scripts[HookCommitMsg], false))
+		info, err := os.Stat(filepath.Join(dir, HookCommitMsg))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+		// Hooks installed by the tool are replaced without force
+		assert.NoError(t, InstallHook(dir, HookCommitMsg, scripts[HookCommitMsg], false))
+
+		removed, err := UninstallHook(dir, HookCommitMsg)
+		assert.NoError(t, err)
+		assert.True(t, removed)
+		assert.NoFileExists(t, filepath.Join(dir, HookCommitMsg))
+
+		removed, err = UninstallHook(dir, HookCommitMsg)
+		assert.NoError(t, err)
+		assert.False(t, removed)
+	})
+
+	t.Run("Foreign hook is kept", func(t *testing.T) {
+		dir := t.TempDir()
+		foreign := "#!/bin/sh\nexit 0\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, HookPrePush), []byte(foreign), 0o600))
+
+		assert.ErrorIs(t, InstallHook(dir, HookPrePush, scripts[HookPrePush], false), ErrForeignHook)
+		_, err := UninstallHook(dir, HookPrePush)
+		assert.ErrorIs(t, err, ErrForeignHook)
+		content, _ := os.ReadFile(filepath.Join(dir, HookPrePush))
+		assert.Equal(t, foreign, string(content))
+
+		assert.NoError(t, InstallHook(dir, HookPrePush, scripts[HookPrePush], true))
+		content, _ = os.ReadFile(filepath.Join(dir, HookPrePush))
+		assert.Equal(t, scripts[HookPrePush], string(content))
+	})
+}
+
+func TestHookScripts(t *testing.T) {
+	scripts := HookScripts(HookOptions{Executable: "/opt/semver gen", ConfigFile: "it's.yaml", Strict: true})
+
+	assert.Len(t, scripts, 2)
+	for name, script := range scripts {
+		assert.Contains(t, script, HookMarker, name)
+		assert.Contains(t, script, `SEMVER_GEN=${SEMVER_GEN:-'/opt/semver gen'}`, name)
+	}
+	assert.Contains(t, scripts[HookCommitMsg], `lint -c 'it'\''s.yaml' --strict "$1"`)
+	assert.Contains(t, scripts[HookPrePush], `verify -l -c 'it'\''s.yaml' --tag "$tag"`)
+	assert.Contains(t, scripts[HookPrePush], "exit 0\n")
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"strings"
+)
+
+// scissorsLine marks the start of the diff appended by `git commit --verbose`
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// LintResult describes the bump level a commit message triggers
+type LintResult struct {
+	Hash        string // Empty for messages not read from commits
+	Subject     string
+	Bump        Bump
+	Keywords    []string // Configured keywords of the level matched in the message
+	Blacklisted []string // Blacklisted terms found in the message
+}
+
+// LintMessage reports which level the message triggers, with the same matching as the version calculation
+func LintMessage(message string, wording Wording, blacklist []string) LintResult {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	result := LintResult{
+		Subject: strings.TrimSpace(subject),
+		Bump:    ClassifyCommit(message, wording, blacklist),
+	}
+
+	var keywords []string
+	switch result.Bump {
+	case BumpMajor:
+		keywords = wording.Major
+	case BumpMinor:
+		keywords = wording.Minor
+	case BumpRelease:
+		keywords = wording.Release
+	case BumpPatch:
+		keywords = wording.Patch
+	}
+	result.Keywords = matchingKeywords(message, keywords, wording.Match)
+	if wording.Gitmoji.Enabled {
+		if level, found := ClassifyGitmoji(message, wording.Gitmoji); level == result.Bump {
+			result.Keywords = append(result.Keywords, found...)
+		}
+	}
+
+	lower := strings.ToLower(message)
+	for _, term := range blacklist {
+		if strings.Contains(lower, strings.ToLower(term)) {
+			result.Blacklisted = append(result.Blacklisted, term)
+		}
+	}
+	return result
+}
+
+// Failed reports whether the message breaks the strict mode rule of matching at least one level.
+// Blacklisted messages are ignored by the calculation on purpose and never fail.
+func (r LintResult) Failed(strict bool) bool {
+	return strict && r.Bump == BumpNone && len(r.Blacklisted) == 0
+}
+
+// CleanCommitMessage strips the comments and the verbose diff git adds to the message file of the commit-msg hook
+func CleanCommitMessage(content string) string {
+	if idx := strings.Index(content, scissorsLine); idx != -1 {
+		content = content[:idx]
+	}
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
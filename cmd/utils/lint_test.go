@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintMessage(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{Patch: []string{"fix"}, Minor: []string{"feat", "add"}, Major: []string{"breaking"}}
+	blacklist := []string{"Merge branch"}
+
+	tests := []struct {
+		name        string
+		message     string
+		bump        Bump
+		keywords    []string
+		blacklisted []string
+		strictFail  bool
+	}{
+		{name: "Minor keyword", message: "feat: add login\n\nBody", bump: BumpMinor, keywords: []string{"feat", "add"}},
+		{name: "Major wins", message: "fix: breaking change", bump: BumpMajor, keywords: []string{"breaking"}},
+		{name: "No keyword", message: "Update readme", bump: BumpNone, strictFail: true},
+		{name: "Blacklisted", message: "Merge branch 'fix-login'", bump: BumpNone, blacklisted: []string{"Merge branch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LintMessage(tt.message, wording, blacklist)
+			assert.Equal(t, tt.bump, result.Bump)
+			assert.Equal(t, tt.keywords, result.Keywords)
+			assert.Equal(t, tt.blacklisted, result.Blacklisted)
+			assert.False(t, result.Failed(false), "Nothing fails outside of strict mode")
+			assert.Equal(t, tt.strictFail, result.Failed(true))
+		})
+	}
+}
+
+func TestLintMessage_prefix(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := ConfigPresets["conventional"].Wording
+	result := LintMessage("feat!: drop the v1 API", wording, nil)
+	assert.Equal(t, BumpMajor, result.Bump)
+	assert.Equal(t, []string{"feat!"}, result.Keywords)
+
+	result = LintMessage("docs: update prefix handling", wording, nil)
+	assert.Equal(t, BumpNone, result.Bump)
+	assert.True(t, result.Failed(true))
+}
+
+func TestLintMessage_gitmoji(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{Patch: []string{"fix"}, Gitmoji: GitmojiWording{Enabled: true}}
+	result := LintMessage("🐛 fix crash, :ambulance: hotfix", wording, nil)
+	assert.Equal(t, BumpPatch, result.Bump)
+	assert.Equal(t, []string{"fix", "🐛", ":ambulance:"}, result.Keywords)
+
+	result = LintMessage(":sparkles: fix the new command", wording, nil)
+	assert.Equal(t, BumpMinor, result.Bump)
+	assert.Equal(t, []string{":sparkles:"}, result.Keywords)
+}
+
+func TestCleanCommitMessage(t *testing.T) {
+	content := "feat: add login\n\nBody\n# Please enter the commit message\n#\n" + scissorsLine + "\ndiff --git a/file b/file\n"
+	assert.Equal(t, "feat: add login\n\nBody", CleanCommitMessage(content))
+}
+
+func TestCommitRange(t *testing.T) {
+	InitLogger(false)
+
+	repo := initTestRepository(t, "Initial commit", "Update readme", "Update docs")
+
+	commits, err := CommitRange(repo, "HEAD~2", "HEAD")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "Update readme", commits[0].Message)
+	assert.Equal(t, "Update docs", commits[1].Message)
+
+	commits, err = CommitRange(repo, "HEAD", "HEAD")
+	assert.NoError(t, err)
+	assert.Empty(t, commits)
+
+	_, err = CommitRange(repo, "v9.9.9", "HEAD")
+	assert.Error(t, err)
+}
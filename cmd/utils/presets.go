@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/template"
+)
+
+// ConfigPreset is a starting configuration written by config init
+type ConfigPreset struct {
+	Description string
+	Wording     Wording
+	Blacklist   []string
+	Strict      bool // Only commits matching the wording change the version
+}
+
+// defaultBlacklist ignores the commits created by merges
+var defaultBlacklist = []string{"Merge branch", "Merge pull request", "Merge remote-tracking branch"}
+
+// ConfigPresets are the built-in configurations, by name
+var ConfigPresets = map[string]ConfigPreset{
+	"conventional": {
+		Description: "Conventional Commits, feat is a minor change, fix and perf are patches, ! or BREAKING CHANGE a major one",
+		Wording: Wording{
+			Patch: []string{"fix", "perf", "revert"},
+			Minor: []string{"feat"},
+			Major: []string{"BREAKING CHANGE", "BREAKING-CHANGE", "feat!", "fix!", "perf!", "refactor!"},
+			Match: MatchPrefix,
+		},
+		Blacklist: defaultBlacklist,
+		Strict:    true,
+	},
+	"angular": {
+		Description: "Angular commit message guidelines, feat is a minor change, fix and perf are patches, BREAKING CHANGE a major one",
+		Wording: Wording{
+			Patch: []string{"fix", "perf"},
+			Minor: []string{"feat"},
+			Major: []string{"BREAKING CHANGE"},
+			Match: MatchPrefix,
+		},
+		Blacklist: defaultBlacklist,
+		Strict:    true,
+	},
+	"gitmoji": {
+		Description: "Gitmoji, ✨ is a minor change, 🐛 and the other fixes are patches, 💥 a major one",
+		Wording: Wording{
+			Gitmoji: GitmojiWording{Enabled: true},
+		},
+		Blacklist: defaultBlacklist,
+		Strict:    true,
+	},
+	"simple": {
+		Description: "plain English keywords, every other commit is a patch",
+		Wording: Wording{
+			Patch: []string{"fix", "update"},
+			Minor: []string{"feature", "improve"},
+			Major: []string{"breaking"},
+		},
+		Blacklist: defaultBlacklist,
+	},
+}
+
+// PresetNames returns the names of the built-in presets, sorted
+func PresetNames() []string {
+	names := make([]string, 0, len(ConfigPresets))
+	for name := range ConfigPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RepositoryFacts is what config init learned from the history of the repository
+type RepositoryFacts struct {
+	TagPrefixes []string // Prefixes of the existing version tags, e.g. "app-"
+	LatestTag   string   // Latest version tag
+	Head        string   // Hash of HEAD, suggested as force.commit when there are no version tags
+}
+
+// prefixedVersionTag matches tags with a prefix in front of the version, e.g. app-1.2.3 or infra/v1.2.3
+var prefixedVersionTag = regexp.MustCompile(`^(.*?[-_/])v?\d+\.\d+\.\d+`)
+
+// DetectTagPrefixes returns the prefixes in front of the versions of the tag names, "v" aside
+func DetectTagPrefixes(names []string) []string {
+	seen := make(map[string]bool)
+	var prefixes []string
+	for _, name := range names {
+		match := prefixedVersionTag.FindStringSubmatch(name)
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		prefixes = append(prefixes, match[1])
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// InspectRepository looks at the history and tags of the prepared repository to complete a preset
+func InspectRepository(repo *GitRepository) (RepositoryFacts, error) {
+	var facts RepositoryFacts
+	commits, err := ListCommits(repo)
+	if err != nil {
+		return facts, err
+	}
+
+	ListExistingTags(repo, nil)
+	names := make([]string, 0, len(repo.Tags)+len(repo.SkippedTags))
+	for _, tag := range repo.Tags {
+		names = append(names, tag.Name)
+	}
+	for _, tag := range repo.SkippedTags {
+		names = append(names, tag.Name)
+	}
+	facts.TagPrefixes = DetectTagPrefixes(names)
+	if _, latest := LatestTagIndex(commits, repo.Tags); latest != "" {
+		facts.LatestTag = latest
+	} else if len(commits) > 0 {
+		facts.Head = commits[len(commits)-1].Hash
+	}
+	return facts, nil
+}
+
+// configTemplate renders a commented configuration in the current schema version
+var configTemplate = template.Must(template.New("config").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(`# semver-generator configuration, {{ .Name }} preset: {{ .Preset.Description }}
+# Documentation: https://github.com/lukaszraczylo/semver-generator
+version: {{ .Version }}
+
+force:
+  # Use the latest version tag as the baseline{{ if .Facts.LatestTag }}, currently {{ .Facts.LatestTag }}{{ end }}
+  existing: true
+  # {{ if .Preset.Strict }}Only commits matching the wording change the version{{ else }}Commits matching no keyword still increment the patch version{{ end }}
+  strict: {{ .Preset.Strict }}
+{{- if .Facts.Head }}
+  # No version tags yet, uncomment to start the calculation at the current commit instead of the first one
+  # commit: {{ .Facts.Head }}
+{{- end }}
+  # Starting version
+  # major: 1
+  # minor: 0
+  # patch: 0
+
+# Commits containing any of these terms {{ if .Preset.Strict }}never change the version{{ else }}never trigger a keyword bump, but still increment the patch version{{ end }}
+blacklist:
+{{- range .Preset.Blacklist }}
+  - {{ quote . }}
+{{- end }}
+{{- if .Facts.TagPrefixes }}
+
+# Prefixes stripped from tag names before parsing the version, "v" is always stripped
+tag_prefixes:
+{{- range .Facts.TagPrefixes }}
+  - {{ quote . }}
+{{- end }}
+{{- end }}
+
+# Keywords looked for in commit messages, levels are checked from major downwards and the first match wins
+wording:
+{{- if .Preset.Wording.Gitmoji.Enabled }}
+  # Emoji and :shortcodes: of https://gitmoji.dev, at the semver level documented there.
+  # Table entries change the level of a gitmoji, as patch, release, minor, major or none.
+  gitmoji:
+    enabled: true
+    # table:
+    #   ":recycle:": patch
+    #   ":lipstick:": none
+{{- end }}
+{{- if eq .Preset.Wording.Match "prefix" }}
+  # Keywords match the type of the subject or of a footer line, e.g. "fix" matches "fix(api): typo" but not "docs: prefix"
+  match: prefix
+{{- end }}
+{{- range .Levels }}
+  {{ .Name }}:
+{{- range .Keywords }}
+    - {{ quote . }}
+{{- end }}
+{{- end }}
+`))
+
+// RenderPresetConfig renders the commented configuration of the named preset, completed with the repository facts
+func RenderPresetConfig(name string, facts RepositoryFacts) (string, error) {
+	preset, ok := ConfigPresets[name]
+	if !ok {
+		return "", fmt.Errorf("unknown preset %q, expected one of %v", name, PresetNames())
+	}
+
+	type level struct {
+		Name     string
+		Keywords []string
+	}
+	var levels []level
+	for _, l := range []level{
+		{Name: "patch", Keywords: preset.Wording.Patch},
+		{Name: "minor", Keywords: preset.Wording.Minor},
+		{Name: "major", Keywords: preset.Wording.Major},
+		{Name: "release", Keywords: preset.Wording.Release},
+	} {
+		if len(l.Keywords) > 0 {
+			levels = append(levels, l)
+		}
+	}
+
+	var b bytes.Buffer
+	err := configTemplate.Execute(&b, map[string]interface{}{
+		"Name":    name,
+		"Preset":  preset,
+		"Facts":   facts,
+		"Version": CurrentConfigVersion,
+		"Levels":  levels,
+	})
+	return b.String(), err
+}
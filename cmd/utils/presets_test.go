@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPresetConfig(t *testing.T) {
+	InitLogger(false)
+
+	facts := RepositoryFacts{TagPrefixes: []string{"app-"}, Head: "0123456789abcdef0123456789abcdef01234567"}
+	for _, name := range PresetNames() {
+		t.Run(name, func(t *testing.T) {
+			content, err := RenderPresetConfig(name, facts)
+			assert.NoError(t, err)
+
+			file := filepath.Join(t.TempDir(), "semver.yaml")
+			assert.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+			issues, err := ValidateConfig(file)
+			assert.NoError(t, err)
+			assert.Empty(t, issues)
+
+			config, err := ReadConfig(file)
+			assert.NoError(t, err)
+			assert.Equal(t, ConfigPresets[name].Wording, config.Wording)
+			assert.Equal(t, ConfigPresets[name].Strict, config.Force.Strict)
+			assert.True(t, config.Force.Existing)
+			assert.Equal(t, []string{"app-"}, config.TagPrefixes)
+			assert.Empty(t, config.Force.Commit, "the suggested commit is commented out")
+			assert.Contains(t, content, "# commit: "+facts.Head)
+			if ConfigPresets[name].Strict {
+				assert.Contains(t, content, "# Commits containing any of these terms never change the version")
+			} else {
+				assert.Contains(t, content, "but still increment the patch version")
+			}
+		})
+	}
+
+	_, err := RenderPresetConfig("unknown", RepositoryFacts{})
+	assert.Error(t, err)
+}
+
+func TestDetectTagPrefixes(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "Plain versions", names: []string{"1.0.0", "v1.2.3", "v2.0.0-rc.1", "latest"}},
+		{
+			name:  "Prefixed versions",
+			names: []string{"app-1.0.0", "app-v1.1.0", "infra/v0.3.0", "tools_2.0.0", "v1.0.0"},
+			want:  []string{"app-", "infra/", "tools_"},
+		},
+		{name: "Build metadata after the version", names: []string{"api-1.2.3-build-4.5.6"}, want: []string{"api-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectTagPrefixes(tt.names))
+		})
+	}
+}
+
+func TestInspectRepository(t *testing.T) {
+	InitLogger(false)
+
+	t.Run("Untagged repository", func(t *testing.T) {
+		repo := initTestRepository(t, "initial commit", "fix: something")
+		facts, err := InspectRepository(repo)
+		assert.NoError(t, err)
+		assert.Empty(t, facts.TagPrefixes)
+		assert.Empty(t, facts.LatestTag)
+		assert.Len(t, facts.Head, 40)
+	})
+
+	t.Run("Tagged repository", func(t *testing.T) {
+		repo := initTestRepository(t, "initial commit")
+		assert.NoError(t, CreateTag(repo, "app-1.0.0", "Release 1.0.0", nil, false))
+		assert.NoError(t, CreateTag(repo, "latest", "Rolling tag", nil, false))
+		facts, err := InspectRepository(repo)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"app-"}, facts.TagPrefixes)
+		assert.Equal(t, "app-1.0.0", facts.LatestTag)
+		assert.Empty(t, facts.Head)
+	})
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrStagedChanges is returned when the index holds changes to files other than the ones to commit
+var ErrStagedChanges = errors.New("unrelated changes are staged")
+
+// DefaultReleaseMessage is the release commit message template used when none is configured
+const DefaultReleaseMessage = "chore(release): v{{ .Version }}"
+
+// Author represents the identity of created commits
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Release represents release commit settings
+type Release struct {
+	Message string // Template of the release commit message
+	Author  Author // Author and committer of the release commit, git configuration when empty
+}
+
+// ReleaseSignature returns the configured author, or the default identity when none is configured
+func ReleaseSignature(repo *GitRepository, author Author) *object.Signature {
+	if author.Name != "" && author.Email != "" {
+		return &object.Signature{Name: author.Name, Email: author.Email, When: time.Now()}
+	}
+	return DefaultSignature(repo)
+}
+
+// CreateCommit commits the files on top of HEAD, signed when signer is set, and returns the commit hash.
+// Paths are relative to the current directory. Fails with ErrStagedChanges when other files are staged.
+// With dryRun set, nothing is staged or committed.
+func CreateCommit(repo *GitRepository, paths []string, message string, author *object.Signature, signer git.Signer, dryRun bool) (string, error) {
+	if repo.Handler == nil {
+		return "", fmt.Errorf("repository is not prepared")
+	}
+	if len(paths) == 0 {
+		return "", errors.New("no files to commit")
+	}
+	if author == nil {
+		return "", errors.New("commit author is not set, configure release.author or git user.name and user.email")
+	}
+
+	worktree, err := repo.Handler.Worktree()
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.Abs(worktree.Filesystem.Root())
+	if err != nil {
+		return "", err
+	}
+
+	files := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s is outside of the repository", path)
+		}
+		files[filepath.ToSlash(rel)] = true
+	}
+
+	// The whole index is committed, changes staged by the user would end up in the release commit
+	status, err := worktree.Status()
+	if err != nil {
+		return "", err
+	}
+	var staged []string
+	for file, fileStatus := range status {
+		if !files[file] && fileStatus.Staging != git.Unmodified && fileStatus.Staging != git.Untracked {
+			staged = append(staged, file)
+		}
+	}
+	if len(staged) > 0 {
+		sort.Strings(staged)
+		return "", fmt.Errorf("%w: %s, commit or unstage them first", ErrStagedChanges, strings.Join(staged, ", "))
+	}
+
+	if dryRun {
+		Info("Dry run, commit not created", map[string]interface{}{
+			"message": message,
+			"files":   paths,
+		})
+		return "", nil
+	}
+
+	for file := range files {
+		if _, err := worktree.Add(file); err != nil {
+			return "", fmt.Errorf("unable to stage %s: %w", file, err)
+		}
+	}
+
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		Author:    author,
+		Committer: author,
+		Signer:    signer,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to commit: %w", err)
+	}
+
+	Debug("Created commit", map[string]interface{}{
+		"commit": hash.String(),
+		"signed": signer != nil,
+	})
+	return hash.String(), nil
+}
+
+// PushBranch pushes the branch checked out in the repository to the named remote
+func PushBranch(repo *GitRepository, remoteName string, dryRun bool) error {
+	_, auth, err := resolveRemote(repo, remoteName)
+	if err != nil {
+		return err
+	}
+
+	head, err := repo.Handler.Head()
+	if err != nil {
+		return err
+	}
+	if !head.Name().IsBranch() {
+		return errors.New("HEAD is detached, check out the branch to push")
+	}
+
+	if dryRun {
+		Info("Dry run, branch not pushed", map[string]interface{}{
+			"branch": head.Name().Short(),
+			"remote": remoteName,
+		})
+		return nil
+	}
+
+	err = repo.Handler.Push(&git.PushOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(head.Name() + ":" + head.Name())},
+		Auth:       auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+
+	Debug("Pushed branch", map[string]interface{}{
+		"branch": head.Name().Short(),
+		"remote": remoteName,
+	})
+	return nil
+}
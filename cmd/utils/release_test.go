@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCommit(t *testing.T) {
+	InitLogger(false)
+
+	repo := initTestRepository(t, "Initial commit")
+	path := filepath.Join(repo.LocalPath, "file.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("1.0.0"), 0o600))
+	author := ReleaseSignature(repo, Author{Name: "Release Bot", Email: "bot@example.com"})
+	head, _ := repo.Handler.Head()
+
+	t.Run("Dry run", func(t *testing.T) {
+		hash, err := CreateCommit(repo, []string{path}, "chore(release): v1.0.0", author, nil, true)
+		assert.NoError(t, err)
+		assert.Empty(t, hash)
+		current, _ := repo.Handler.Head()
+		assert.Equal(t, head.Hash(), current.Hash(), "HEAD should not move")
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		hash, err := CreateCommit(repo, []string{path}, "chore(release): v1.0.0", author, nil, false)
+		assert.NoError(t, err)
+
+		commit, err := repo.Handler.CommitObject(plumbing.NewHash(hash))
+		assert.NoError(t, err)
+		assert.Equal(t, "chore(release): v1.0.0", commit.Message)
+		assert.Equal(t, "Release Bot", commit.Author.Name)
+		assert.Equal(t, "bot@example.com", commit.Committer.Email)
+		assert.Equal(t, head.Hash(), commit.ParentHashes[0])
+
+		file, err := commit.File("file.txt")
+		assert.NoError(t, err)
+		content, _ := file.Contents()
+		assert.Equal(t, "1.0.0", content)
+	})
+
+	t.Run("Missing author", func(t *testing.T) {
+		_, err := CreateCommit(repo, []string{path}, "chore(release): v1.0.0", nil, nil, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unrelated staged changes", func(t *testing.T) {
+		worktree, _ := repo.Handler.Worktree()
+		assert.NoError(t, os.WriteFile(filepath.Join(repo.LocalPath, "other.txt"), []byte("work in progress"), 0o600))
+		_, err := worktree.Add("other.txt")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, []byte("1.1.0"), 0o600))
+		before, _ := repo.Handler.Head()
+
+		_, err = CreateCommit(repo, []string{path}, "chore(release): v1.1.0", author, nil, false)
+		assert.ErrorIs(t, err, ErrStagedChanges)
+		assert.ErrorContains(t, err, "other.txt")
+		current, _ := repo.Handler.Head()
+		assert.Equal(t, before.Hash(), current.Hash(), "HEAD should not move")
+
+		_, err = worktree.Remove("other.txt")
+		assert.NoError(t, err)
+		hash, err := CreateCommit(repo, []string{path}, "chore(release): v1.1.0", author, nil, false)
+		assert.NoError(t, err)
+		commit, _ := repo.Handler.CommitObject(plumbing.NewHash(hash))
+		_, err = commit.File("other.txt")
+		assert.Error(t, err, "unstaged files stay out of the release commit")
+	})
+
+	t.Run("File outside of repository", func(t *testing.T) {
+		outside := filepath.Join(t.TempDir(), "file.txt")
+		_, err := CreateCommit(repo, []string{outside}, "chore(release): v1.0.0", author, nil, false)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,92 @@
+package utils
+
+import "strconv"
+
+// ReleaseReport describes the calculated version against the previous release
+type ReleaseReport struct {
+	Version         SemVer
+	PreviousTag     string // Empty when there is no previous release
+	PreviousVersion SemVer
+	Commits         []CommitDetails // Commits since the previous release, oldest first
+	Bumps           []Bump          // Bump level triggered by each of the commits
+}
+
+// ReleaseOutput represents a named value describing the release, e.g. a step output or an environment variable
+type ReleaseOutput struct {
+	Name  string
+	Value string
+}
+
+// NewReleaseReport classifies the commits since the previous release.
+// With rulesAt set, each commit is classified with its own rules instead of wording and blacklist.
+func NewReleaseReport(version SemVer, previousTag string, tagPrefixes []string, commits []CommitDetails, wording Wording, blacklist []string, rulesAt RulesAt) ReleaseReport {
+	report := ReleaseReport{
+		Version:     version,
+		PreviousTag: previousTag,
+		Commits:     commits,
+	}
+	if previousTag != "" {
+		report.PreviousVersion = ParseExistingSemver(previousTag, SemVer{}, tagPrefixes)
+	}
+	for _, commit := range commits {
+		commitWording, commitBlacklist := rulesAt.Of(commit.Hash, wording, blacklist)
+		report.Bumps = append(report.Bumps, ClassifyCommit(commit.Message, commitWording, commitBlacklist))
+	}
+	return report
+}
+
+// Bump returns the level by which the version changed since the previous release
+func (r ReleaseReport) Bump() Bump {
+	return CompareBump(r.PreviousVersion, r.Version)
+}
+
+// ReleaseNeeded reports whether the version differs from the previous release
+func (r ReleaseReport) ReleaseNeeded() bool {
+	return r.Bump() != BumpNone
+}
+
+// Prerelease returns the pre-release part of the version (e.g. "rc.1"), empty for regular releases
+func (r ReleaseReport) Prerelease() string {
+	if !r.Version.EnableReleaseCandidate {
+		return ""
+	}
+	return "rc." + strconv.Itoa(r.Version.Release)
+}
+
+// PreviousVersionString returns the previous version, empty when there is no previous release
+func (r ReleaseReport) PreviousVersionString() string {
+	if r.PreviousTag == "" {
+		return ""
+	}
+	return FormatSemver(r.PreviousVersion)
+}
+
+// Outputs returns the values describing the release, in the documented order
+func (r ReleaseReport) Outputs() []ReleaseOutput {
+	return []ReleaseOutput{
+		{Name: "version", Value: FormatSemver(r.Version)},
+		{Name: "major", Value: strconv.Itoa(r.Version.Major)},
+		{Name: "minor", Value: strconv.Itoa(r.Version.Minor)},
+		{Name: "patch", Value: strconv.Itoa(r.Version.Patch)},
+		{Name: "prerelease", Value: r.Prerelease()},
+		{Name: "previous_version", Value: r.PreviousVersionString()},
+		{Name: "bump", Value: r.Bump().String()},
+		{Name: "release_needed", Value: strconv.FormatBool(r.ReleaseNeeded())},
+	}
+}
+
+// CompareBump returns the most significant level changed between two versions
+func CompareBump(previous SemVer, next SemVer) Bump {
+	switch {
+	case previous.Major != next.Major:
+		return BumpMajor
+	case previous.Minor != next.Minor:
+		return BumpMinor
+	case next.EnableReleaseCandidate && (!previous.EnableReleaseCandidate || previous.Release != next.Release):
+		return BumpRelease
+	case previous.Patch != next.Patch || previous.EnableReleaseCandidate != next.EnableReleaseCandidate:
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareBump(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous SemVer
+		next     SemVer
+		want     Bump
+	}{
+		{name: "Unchanged", previous: SemVer{Patch: 1}, next: SemVer{Patch: 1}, want: BumpNone},
+		{name: "Patch", previous: SemVer{Patch: 1}, next: SemVer{Patch: 2}, want: BumpPatch},
+		{name: "Minor resets patch", previous: SemVer{Minor: 1, Patch: 4}, next: SemVer{Minor: 2}, want: BumpMinor},
+		{name: "Major", previous: SemVer{Major: 1, Minor: 3}, next: SemVer{Major: 2}, want: BumpMajor},
+		{name: "Release candidate", previous: SemVer{Minor: 1, Release: 1, EnableReleaseCandidate: true}, next: SemVer{Minor: 1, Release: 2, EnableReleaseCandidate: true}, want: BumpRelease},
+		{name: "Promoted release candidate", previous: SemVer{Minor: 1, Release: 2, EnableReleaseCandidate: true}, next: SemVer{Minor: 1}, want: BumpPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareBump(tt.previous, tt.next))
+		})
+	}
+}
+
+func TestNewReleaseReport(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{Patch: []string{"fix"}, Minor: []string{"feat"}}
+	commits := []CommitDetails{
+		{Hash: "1111111111", Message: "fix: typo"},
+		{Hash: "2222222222", Message: "feat: login"},
+	}
+
+	t.Run("With previous tag", func(t *testing.T) {
+		report := NewReleaseReport(SemVer{Minor: 2}, "v0.1.3", nil, commits, wording, nil, nil)
+		assert.Equal(t, "0.1.3", report.PreviousVersionString())
+		assert.Equal(t, []Bump{BumpPatch, BumpMinor}, report.Bumps)
+		assert.Equal(t, BumpMinor, report.Bump())
+		assert.True(t, report.ReleaseNeeded())
+		assert.Equal(t, "", report.Prerelease())
+	})
+
+	t.Run("Without previous tag", func(t *testing.T) {
+		report := NewReleaseReport(SemVer{Patch: 1, Release: 3, EnableReleaseCandidate: true}, "", nil, nil, wording, nil, nil)
+		assert.Equal(t, "", report.PreviousVersionString())
+		assert.Equal(t, "rc.3", report.Prerelease())
+		assert.Equal(t, BumpRelease, report.Bump())
+	})
+
+	t.Run("Already released", func(t *testing.T) {
+		report := NewReleaseReport(SemVer{Patch: 3}, "v0.0.3", nil, nil, wording, nil, nil)
+		assert.Equal(t, BumpNone, report.Bump())
+		assert.False(t, report.ReleaseNeeded())
+	})
+}
@@ -1,15 +1,115 @@
 package utils
 
 import (
+	"fmt"
 	"strings"
 )
 
-// CalculateSemver calculates the semantic version based on commit messages
+// Bump represents the version level a commit triggers
+type Bump int
+
+const (
+	// BumpNone means no keyword matched
+	BumpNone Bump = iota
+	// BumpPatch means a patch keyword matched
+	BumpPatch
+	// BumpRelease means a release candidate keyword matched
+	BumpRelease
+	// BumpMinor means a minor keyword matched
+	BumpMinor
+	// BumpMajor means a major keyword matched
+	BumpMajor
+)
+
+// String returns the name of the bump level
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpRelease:
+		return "release"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseBump returns the bump level of the name, as returned by Bump.String
+func ParseBump(name string) (Bump, error) {
+	for _, bump := range []Bump{BumpNone, BumpPatch, BumpRelease, BumpMinor, BumpMajor} {
+		if strings.EqualFold(name, bump.String()) {
+			return bump, nil
+		}
+	}
+	return BumpNone, fmt.Errorf("unknown level %q, expected patch, release, minor or major", name)
+}
+
+// ClassifyCommit returns the bump level triggered by the commit message.
+// Levels are checked from major downwards, the first match wins. With gitmoji enabled,
+// the higher of the keyword and the gitmoji levels is returned.
+func ClassifyCommit(message string, wording Wording, blacklist []string) Bump {
+	bump := classifyKeywords(message, wording, blacklist)
+	if wording.Gitmoji.Enabled && bump != BumpMajor {
+		if level, _ := ClassifyGitmoji(message, wording.Gitmoji); level > bump && !IsBlacklisted(message, blacklist) {
+			bump = level
+		}
+	}
+	return bump
+}
+
+// classifyKeywords returns the bump level of the first level with a keyword matching the message
+func classifyKeywords(message string, wording Wording, blacklist []string) Bump {
+	commitSlice := strings.Fields(message)
+	matches := func(targets []string) bool {
+		if wording.Match == MatchPrefix {
+			return CheckPrefixMatches(message, targets, blacklist)
+		}
+		return CheckMatches(commitSlice, targets, blacklist)
+	}
+	switch {
+	case matches(wording.Major):
+		return BumpMajor
+	case matches(wording.Minor):
+		return BumpMinor
+	case matches(wording.Release):
+		return BumpRelease
+	case matches(wording.Patch):
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+// LatestTagIndex returns the index of the latest tagged commit and the name of its tag,
+// or -1 when none of the commits is tagged. Commits are expected oldest-first.
+func LatestTagIndex(commits []CommitDetails, tags []TagDetails) (int, string) {
+	latestTagIndex := -1
+	var latestTagName string
+
+	for i, commit := range commits {
+		for _, tag := range tags {
+			if commit.Hash == tag.Hash {
+				if i > latestTagIndex {
+					latestTagIndex = i
+					latestTagName = tag.Name
+				}
+			}
+		}
+	}
+	return latestTagIndex, latestTagName
+}
+
+// CalculateSemver calculates the semantic version based on commit messages.
+// With rulesAt set, each commit is classified with its own rules instead of wording and blacklist.
 func CalculateSemver(
 	commits []CommitDetails,
 	tags []TagDetails,
 	wording Wording,
 	blacklist []string,
+	rulesAt RulesAt,
 	initialSemver SemVer,
 	respectExisting bool,
 	strictMode bool,
@@ -20,20 +120,7 @@ func CalculateSemver(
 
 	// If respecting existing tags, find the latest tagged commit and start from there
 	if respectExisting && len(tags) > 0 {
-		latestTagIndex := -1
-		var latestTagName string
-
-		// Find the latest tagged commit (highest index since commits are oldest-first)
-		for i, commit := range commits {
-			for _, tag := range tags {
-				if commit.Hash == tag.Hash {
-					if i > latestTagIndex {
-						latestTagIndex = i
-						latestTagName = tag.Name
-					}
-				}
-			}
-		}
+		latestTagIndex, latestTagName := LatestTagIndex(commits, tags)
 
 		// If we found a tagged commit, use its version and start processing after it
 		if latestTagIndex >= 0 {
@@ -47,67 +134,60 @@ func CalculateSemver(
 	}
 
 	for _, commit := range commits[startIndex:] {
-		// In non-strict mode, increment patch by default
-		if !strictMode {
-			semver.Patch++
-			Debug("Incrementing patch (DEFAULT)", map[string]interface{}{
-				"commit": strings.TrimSuffix(commit.Message, "\n"),
-				"semver": FormatSemver(semver),
-			})
-		}
-
-		// Check for keyword matches
-		commitSlice := strings.Fields(commit.Message)
-		matchPatch := CheckMatches(commitSlice, wording.Patch, blacklist)
-		matchMinor := CheckMatches(commitSlice, wording.Minor, blacklist)
-		matchMajor := CheckMatches(commitSlice, wording.Major, blacklist)
-		matchReleaseCandidate := CheckMatches(commitSlice, wording.Release, blacklist)
-
-		// Apply version changes based on matches
-		if matchMajor {
-			semver.Major++
-			semver.Minor = 0
-			semver.Patch = 1
-			semver.EnableReleaseCandidate = false
-			semver.Release = 0
-			Debug("Incrementing major (WORDING)", map[string]interface{}{
-				"commit": strings.TrimSuffix(commit.Message, "\n"),
-				"semver": FormatSemver(semver),
-			})
-			continue
-		}
+		commitWording, commitBlacklist := rulesAt.Of(commit.Hash, wording, blacklist)
+		semver = nextSemver(semver, commit.Message, ClassifyCommit(commit.Message, commitWording, commitBlacklist), strictMode)
+	}
 
-		if matchMinor {
-			semver.Minor++
-			semver.Patch = 1
-			semver.EnableReleaseCandidate = false
-			semver.Release = 0
-			Debug("Incrementing minor (WORDING)", map[string]interface{}{
-				"commit": strings.TrimSuffix(commit.Message, "\n"),
-				"semver": FormatSemver(semver),
-			})
-			continue
-		}
+	return semver
+}
 
-		if matchReleaseCandidate {
-			semver.Release++
-			semver.Patch = 1
-			semver.EnableReleaseCandidate = true
-			Debug("Incrementing release candidate (WORDING)", map[string]interface{}{
-				"commit": strings.TrimSuffix(commit.Message, "\n"),
-				"semver": FormatSemver(semver),
-			})
-			continue
-		}
+// nextSemver applies the bump triggered by the commit message to the version.
+// Outside of strict mode every commit increments the patch version.
+func nextSemver(semver SemVer, message string, bump Bump, strictMode bool) SemVer {
+	// In non-strict mode, increment patch by default
+	if !strictMode {
+		semver.Patch++
+		Debug("Incrementing patch (DEFAULT)", map[string]interface{}{
+			"commit": strings.TrimSuffix(message, "\n"),
+			"semver": FormatSemver(semver),
+		})
+	}
 
-		if matchPatch {
-			semver.Patch++
-			Debug("Incrementing patch (WORDING)", map[string]interface{}{
-				"commit": strings.TrimSuffix(commit.Message, "\n"),
-				"semver": FormatSemver(semver),
-			})
-			continue
-		}
+	// Apply version changes based on keyword matches
+	switch bump {
+	case BumpMajor:
+		semver.Major++
+		semver.Minor = 0
+		semver.Patch = 1
+		semver.EnableReleaseCandidate = false
+		semver.Release = 0
+		Debug("Incrementing major (WORDING)", map[string]interface{}{
+			"commit": strings.TrimSuffix(message, "\n"),
+			"semver": FormatSemver(semver),
+		})
+	case BumpMinor:
+		semver.Minor++
+		semver.Patch = 1
+		semver.EnableReleaseCandidate = false
+		semver.Release = 0
+		Debug("Incrementing minor (WORDING)", map[string]interface{}{
+			"commit": strings.TrimSuffix(message, "\n"),
+			"semver": FormatSemver(semver),
+		})
+	case BumpRelease:
+		semver.Release++
+		semver.Patch = 1
+		semver.EnableReleaseCandidate = true
+		Debug("Incrementing release candidate (WORDING)", map[string]interface{}{
+			"commit": strings.TrimSuffix(message, "\n"),
+			"semver": FormatSemver(semver),
+		})
+	case BumpPatch:
+		semver.Patch++
+		Debug("Incrementing patch (WORDING)", map[string]interface{}{
+			"commit": strings.TrimSuffix(message, "\n"),
+			"semver": FormatSemver(semver),
+		})
 	}
 
 	return semver
@@ -282,6 +282,7 @@ func TestCalculateSemver(t *testing.T) {
 				tt.tags,
 				tt.wording,
 				tt.blacklist,
+				nil,
 				tt.initialSemver,
 				tt.respectExisting,
 				tt.strictMode,
@@ -296,3 +297,87 @@ func TestCalculateSemver(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyCommit(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{
+		Patch:   []string{"fix"},
+		Minor:   []string{"feat"},
+		Major:   []string{"breaking"},
+		Release: []string{"rc"},
+	}
+	tests := []struct {
+		message string
+		want    Bump
+	}{
+		{"fix: crash", BumpPatch},
+		{"feat: new command", BumpMinor},
+		{"feat: breaking change of flags", BumpMajor},
+		{"rc for next release", BumpRelease},
+		{"docs: readme", BumpNone},
+		{"fix: Merge branch main", BumpNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			got := ClassifyCommit(tt.message, wording, []string{"Merge branch"})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Gitmoji raise the level of the keywords, but are ignored on blacklisted commits as well
+	wording.Gitmoji = GitmojiWording{Enabled: true}
+	for _, tt := range []struct {
+		message string
+		want    Bump
+	}{
+		{"💥 drop the v1 API", BumpMajor},
+		{":sparkles: new command", BumpMinor},
+		{"fix: ✨ crash", BumpMinor},
+		{"feat: 🐛 login", BumpMinor},
+		{"📝 docs", BumpNone},
+		{"✨ Merge branch main", BumpNone},
+	} {
+		t.Run(tt.message, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyCommit(tt.message, wording, []string{"Merge branch"}))
+		})
+	}
+
+	// Conventional Commits only match on the type, unrelated words containing the keywords do not bump
+	for _, tt := range []struct {
+		message string
+		want    Bump
+	}{
+		{"docs: update prefix handling", BumpNone},
+		{"chore(deps): update the feature flags", BumpNone},
+		{"fix(api): typo", BumpPatch},
+		{"perf: faster parsing", BumpPatch},
+		{"feat: export", BumpMinor},
+		{"feat!: drop the v1 API", BumpMajor},
+		{"refactor(core)!: drop the v1 API", BumpMajor},
+		{"feat: export\n\nBREAKING CHANGE: the CSV format changed", BumpMajor},
+	} {
+		t.Run(tt.message, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyCommit(tt.message, ConfigPresets["conventional"].Wording, nil))
+		})
+	}
+
+	assert.Equal(t, "major", BumpMajor.String())
+	assert.Equal(t, "none", BumpNone.String())
+}
+
+func TestParseBump(t *testing.T) {
+	for _, bump := range []Bump{BumpNone, BumpPatch, BumpRelease, BumpMinor, BumpMajor} {
+		got, err := ParseBump(bump.String())
+		assert.NoError(t, err)
+		assert.Equal(t, bump, got)
+	}
+
+	got, err := ParseBump("Minor")
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMinor, got)
+
+	_, err = ParseBump("huge")
+	assert.Error(t, err)
+}
@@ -0,0 +1,379 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SigningFormatOpenPGP signs tags with an OpenPGP key
+	SigningFormatOpenPGP = "openpgp"
+	// SigningFormatSSH signs tags with an SSH key
+	SigningFormatSSH = "ssh"
+
+	// SigningKeyEnv holds the private signing key itself, e.g. from a CI secret
+	SigningKeyEnv = "SEMVER_SIGNING_KEY"
+	// SigningPassphraseEnv holds the passphrase of the private signing key
+	SigningPassphraseEnv = "SEMVER_SIGNING_PASSPHRASE"
+
+	sshSignatureArmorStart = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureArmorEnd   = "-----END SSH SIGNATURE-----"
+	sshSignatureNamespace  = "git"
+)
+
+// sshSignatureMagic is the preamble of the SSHSIG format
+var sshSignatureMagic = [6]byte{'S', 'S', 'H', 'S', 'I', 'G'}
+
+// Signing represents tag signing settings
+type Signing struct {
+	Format            string // "openpgp" or "ssh", detected from the key when empty
+	Key               string // Path to the private key, SEMVER_SIGNING_KEY takes precedence
+	PassphraseFile    string `mapstructure:"passphrase_file"`
+	RequireSignedTags bool   `mapstructure:"require_signed_tags"`
+	TrustedKeys       string `mapstructure:"trusted_keys"` // Armored OpenPGP keyring or SSH public keys (authorized_keys format)
+}
+
+// TagVerifier checks the signature of an annotated tag
+type TagVerifier func(tag *object.Tag) error
+
+// LoadSigner returns the signer for the configured signing key, or nil when no key is configured
+func LoadSigner(signing Signing) (git.Signer, error) {
+	key, err := readSigningKey(signing)
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	passphrase, err := readSigningPassphrase(signing)
+	if err != nil {
+		return nil, err
+	}
+
+	switch signingFormat(signing.Format, key) {
+	case SigningFormatSSH:
+		return loadSSHSigner(key, passphrase)
+	case SigningFormatOpenPGP:
+		return loadOpenPGPSigner(key, passphrase)
+	default:
+		return nil, fmt.Errorf("unsupported signing format %q", signing.Format)
+	}
+}
+
+// NewTagVerifier returns a verifier accepting tags signed by one of the trusted keys.
+// The public part of the signing key is trusted as well.
+func NewTagVerifier(signing Signing) (TagVerifier, error) {
+	var keyring openpgp.EntityList
+	var sshKeys []ssh.PublicKey
+
+	if signing.TrustedKeys != "" {
+		// #nosec G304 -- path comes from the user's own configuration
+		trusted, err := os.ReadFile(signing.TrustedKeys)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read trusted keys: %w", err)
+		}
+		if bytes.Contains(trusted, []byte("BEGIN PGP PUBLIC KEY BLOCK")) {
+			keyring, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(trusted))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse trusted OpenPGP keys: %w", err)
+			}
+		} else {
+			sshKeys, err = parseSSHPublicKeys(trusted)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	signer, err := LoadSigner(signing)
+	if err != nil {
+		return nil, err
+	}
+	switch s := signer.(type) {
+	case *openPGPSigner:
+		keyring = append(keyring, s.entity)
+	case *sshSigner:
+		sshKeys = append(sshKeys, s.signer.PublicKey())
+	}
+
+	if len(keyring) == 0 && len(sshKeys) == 0 {
+		return nil, fmt.Errorf("require_signed_tags is set but no trusted or signing keys are configured")
+	}
+
+	return func(tag *object.Tag) error {
+		if tag.PGPSignature == "" {
+			return fmt.Errorf("tag %s is not signed", tag.Name)
+		}
+
+		encoded := &plumbing.MemoryObject{}
+		if err := tag.EncodeWithoutSignature(encoded); err != nil {
+			return err
+		}
+		reader, err := encoded.Reader()
+		if err != nil {
+			return err
+		}
+		message, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(tag.PGPSignature, sshSignatureArmorStart) {
+			return verifySSHSignature(tag.PGPSignature, message, sshKeys)
+		}
+		if len(keyring) == 0 {
+			return fmt.Errorf("no trusted OpenPGP keys to verify tag %s", tag.Name)
+		}
+		_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(message), strings.NewReader(tag.PGPSignature), nil)
+		return err
+	}, nil
+}
+
+// readSigningKey returns the private key from SEMVER_SIGNING_KEY or the configured file
+func readSigningKey(signing Signing) ([]byte, error) {
+	if key := os.Getenv(SigningKeyEnv); key != "" {
+		return []byte(key), nil
+	}
+	if signing.Key == "" {
+		return nil, nil
+	}
+	// #nosec G304 -- path comes from the user's own configuration
+	key, err := os.ReadFile(signing.Key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read signing key: %w", err)
+	}
+	return key, nil
+}
+
+// readSigningPassphrase returns the passphrase from SEMVER_SIGNING_PASSPHRASE or the configured file
+func readSigningPassphrase(signing Signing) ([]byte, error) {
+	if passphrase := os.Getenv(SigningPassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	if signing.PassphraseFile == "" {
+		return nil, nil
+	}
+	// #nosec G304 -- path comes from the user's own configuration
+	passphrase, err := os.ReadFile(signing.PassphraseFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read signing passphrase: %w", err)
+	}
+	return bytes.TrimRight(passphrase, "\r\n"), nil
+}
+
+// signingFormat returns the configured format, detecting it from the key when not set
+func signingFormat(format string, key []byte) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	if bytes.Contains(key, []byte("BEGIN PGP PRIVATE KEY BLOCK")) {
+		return SigningFormatOpenPGP
+	}
+	return SigningFormatSSH
+}
+
+// openPGPSigner signs with an OpenPGP private key
+type openPGPSigner struct {
+	entity *openpgp.Entity
+}
+
+// loadOpenPGPSigner parses an armored OpenPGP private key, decrypting it with the passphrase if needed
+func loadOpenPGPSigner(key []byte, passphrase []byte) (*openPGPSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse OpenPGP key: %w", err)
+	}
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			if len(passphrase) == 0 {
+				return nil, fmt.Errorf("OpenPGP key is encrypted, set %s or signing.passphrase_file", SigningPassphraseEnv)
+			}
+			if err := entity.DecryptPrivateKeys(passphrase); err != nil {
+				return nil, fmt.Errorf("unable to decrypt OpenPGP key: %w", err)
+			}
+		}
+		return &openPGPSigner{entity: entity}, nil
+	}
+	return nil, fmt.Errorf("no OpenPGP private key found")
+}
+
+// Sign returns the armored detached signature of the message
+func (s *openPGPSigner) Sign(message io.Reader) ([]byte, error) {
+	var b bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&b, s.entity, message, nil); err != nil {
+		return nil, err
+	}
+	b.WriteString("\n")
+	return b.Bytes(), nil
+}
+
+// sshSigner signs with an SSH private key using the SSHSIG format used by git
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+// loadSSHSigner parses an SSH private key, decrypting it with the passphrase if needed
+func loadSSHSigner(key []byte, passphrase []byte) (*sshSigner, error) {
+	var signer ssh.Signer
+	var err error
+	if len(passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, passphrase)
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("SSH key is encrypted, set %s or signing.passphrase_file", SigningPassphraseEnv)
+		}
+		return nil, fmt.Errorf("unable to parse SSH key: %w", err)
+	}
+	return &sshSigner{signer: signer}, nil
+}
+
+// sshSignedData is the blob signed in the SSHSIG format
+type sshSignedData struct {
+	Magic         [6]byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// sshSignatureBlob is the SSHSIG signature envelope
+type sshSignatureBlob struct {
+	Magic         [6]byte
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// Sign returns the armored SSHSIG signature of the message
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(message)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha512.Sum512(data)
+	signedData := ssh.Marshal(sshSignedData{
+		Magic:         sshSignatureMagic,
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          hash[:],
+	})
+
+	var signature *ssh.Signature
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := ssh.Marshal(sshSignatureBlob{
+		Magic:         sshSignatureMagic,
+		Version:       1,
+		PublicKey:     s.signer.PublicKey().Marshal(),
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(signature),
+	})
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var b strings.Builder
+	b.WriteString(sshSignatureArmorStart + "\n")
+	for len(encoded) > 70 {
+		b.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	b.WriteString(encoded + "\n" + sshSignatureArmorEnd + "\n")
+	return []byte(b.String()), nil
+}
+
+// verifySSHSignature checks an armored SSHSIG signature of the message against the trusted keys
+func verifySSHSignature(armored string, message []byte, trusted []ssh.PublicKey) error {
+	body := strings.TrimSpace(armored)
+	body = strings.TrimPrefix(body, sshSignatureArmorStart)
+	body = strings.TrimSuffix(body, sshSignatureArmorEnd)
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return fmt.Errorf("invalid SSH signature encoding: %w", err)
+	}
+
+	var blob sshSignatureBlob
+	if err := ssh.Unmarshal(raw, &blob); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	if blob.Magic != sshSignatureMagic || blob.Version != 1 || blob.Namespace != sshSignatureNamespace {
+		return fmt.Errorf("invalid SSH signature envelope")
+	}
+
+	var hash []byte
+	switch blob.HashAlgorithm {
+	case "sha512":
+		sum := sha512.Sum512(message)
+		hash = sum[:]
+	case "sha256":
+		sum := sha256.Sum256(message)
+		hash = sum[:]
+	default:
+		return fmt.Errorf("unsupported SSH signature hash %q", blob.HashAlgorithm)
+	}
+
+	var trustedKey ssh.PublicKey
+	for _, key := range trusted {
+		if bytes.Equal(key.Marshal(), blob.PublicKey) {
+			trustedKey = key
+			break
+		}
+	}
+	if trustedKey == nil {
+		return fmt.Errorf("SSH signature made by untrusted key")
+	}
+
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(blob.Signature, &signature); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	return trustedKey.Verify(ssh.Marshal(sshSignedData{
+		Magic:         sshSignatureMagic,
+		Namespace:     blob.Namespace,
+		Reserved:      blob.Reserved,
+		HashAlgorithm: blob.HashAlgorithm,
+		Hash:          hash,
+	}), &signature)
+}
+
+// parseSSHPublicKeys parses public keys in authorized_keys or allowed_signers format
+func parseSSHPublicKeys(data []byte) ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+	for len(bytes.TrimSpace(data)) > 0 {
+		key, _, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse trusted SSH keys: %w", err)
+		}
+		keys = append(keys, key)
+		data = rest
+	}
+	return keys, nil
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// writeOpenPGPKey generates an OpenPGP key, optionally encrypted, and writes the armored private key
+func writeOpenPGPKey(t *testing.T, passphrase string) (string, *openpgp.Entity) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Test Author", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate OpenPGP key: %v", err)
+	}
+	if passphrase != "" {
+		if err := entity.EncryptPrivateKeys([]byte(passphrase), nil); err != nil {
+			t.Fatalf("Failed to encrypt OpenPGP key: %v", err)
+		}
+	}
+
+	var b bytes.Buffer
+	w, err := armor.Encode(&b, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("Failed to armor OpenPGP key: %v", err)
+	}
+	if err := entity.SerializePrivateWithoutSigning(w, nil); err != nil {
+		t.Fatalf("Failed to serialize OpenPGP key: %v", err)
+	}
+	_ = w.Close()
+
+	path := filepath.Join(t.TempDir(), "private.asc")
+	if err := os.WriteFile(path, b.Bytes(), 0o600); err != nil {
+		t.Fatalf("Failed to write OpenPGP key: %v", err)
+	}
+	return path, entity
+}
+
+// generateSSHKey generates an ed25519 SSH key, optionally encrypted, returning the PEM private key
+func generateSSHKey(t *testing.T, passphrase string) ([]byte, ssh.PublicKey) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate SSH key: %v", err)
+	}
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(private, "test", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(private, "test")
+	}
+	if err != nil {
+		t.Fatalf("Failed to marshal SSH key: %v", err)
+	}
+	sshPublic, _ := ssh.NewPublicKey(public)
+	return pem.EncodeToMemory(block), sshPublic
+}
+
+func TestLoadSigner(t *testing.T) {
+	InitLogger(false)
+
+	t.Run("No key configured", func(t *testing.T) {
+		signer, err := LoadSigner(Signing{})
+		assert.NoError(t, err)
+		assert.Nil(t, signer)
+	})
+
+	t.Run("Encrypted OpenPGP key with passphrase file", func(t *testing.T) {
+		keyPath, _ := writeOpenPGPKey(t, "secret")
+		passphrasePath := filepath.Join(t.TempDir(), "passphrase")
+		assert.NoError(t, os.WriteFile(passphrasePath, []byte("secret\n"), 0o600))
+
+		signer, err := LoadSigner(Signing{Key: keyPath, PassphraseFile: passphrasePath})
+		assert.NoError(t, err)
+		assert.IsType(t, &openPGPSigner{}, signer)
+	})
+
+	t.Run("Encrypted OpenPGP key without passphrase", func(t *testing.T) {
+		keyPath, _ := writeOpenPGPKey(t, "secret")
+		_, err := LoadSigner(Signing{Key: keyPath})
+		assert.ErrorContains(t, err, SigningPassphraseEnv)
+	})
+
+	t.Run("SSH key from environment", func(t *testing.T) {
+		key, _ := generateSSHKey(t, "secret")
+		t.Setenv(SigningKeyEnv, string(key))
+		t.Setenv(SigningPassphraseEnv, "secret")
+
+		signer, err := LoadSigner(Signing{})
+		assert.NoError(t, err)
+		assert.IsType(t, &sshSigner{}, signer)
+	})
+
+	t.Run("Missing key file", func(t *testing.T) {
+		_, err := LoadSigner(Signing{Key: "non-existent-key"})
+		assert.Error(t, err)
+	})
+}
+
+func TestSignedTags(t *testing.T) {
+	InitLogger(false)
+
+	t.Run("OpenPGP signed tag is verified", func(t *testing.T) {
+		keyPath, _ := writeOpenPGPKey(t, "")
+		signing := Signing{Key: keyPath}
+		signer, err := LoadSigner(signing)
+		assert.NoError(t, err)
+
+		repo := initTestRepository(t, "Initial commit")
+		assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", signer, false))
+
+		ref, _ := repo.Handler.Tag("v0.0.1")
+		tagObj, err := repo.Handler.TagObject(ref.Hash())
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(tagObj.PGPSignature, "-----BEGIN PGP SIGNATURE-----"))
+
+		verifier, err := NewTagVerifier(signing)
+		assert.NoError(t, err)
+		assert.NoError(t, verifier(tagObj))
+
+		// A different trusted key must not accept the signature
+		_, other := writeOpenPGPKey(t, "")
+		var public bytes.Buffer
+		w, _ := armor.Encode(&public, openpgp.PublicKeyType, nil)
+		_ = other.Serialize(w)
+		_ = w.Close()
+		trustedPath := filepath.Join(t.TempDir(), "trusted.asc")
+		assert.NoError(t, os.WriteFile(trustedPath, public.Bytes(), 0o600))
+		otherVerifier, err := NewTagVerifier(Signing{TrustedKeys: trustedPath})
+		assert.NoError(t, err)
+		assert.Error(t, otherVerifier(tagObj))
+	})
+
+	t.Run("SSH signed tag is verified", func(t *testing.T) {
+		key, public := generateSSHKey(t, "")
+		t.Setenv(SigningKeyEnv, string(key))
+		signer, err := LoadSigner(Signing{Format: SigningFormatSSH})
+		assert.NoError(t, err)
+
+		repo := initTestRepository(t, "Initial commit")
+		assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", signer, false))
+
+		ref, _ := repo.Handler.Tag("v0.0.1")
+		tagObj, err := repo.Handler.TagObject(ref.Hash())
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(tagObj.PGPSignature, sshSignatureArmorStart))
+		assert.Equal(t, "Release 0.0.1\n", tagObj.Message)
+
+		// Trusted keys in allowed_signers format
+		os.Unsetenv(SigningKeyEnv)
+		trustedPath := filepath.Join(t.TempDir(), "allowed_signers")
+		assert.NoError(t, os.WriteFile(trustedPath, []byte("test@example.com "+string(ssh.MarshalAuthorizedKey(public))), 0o600))
+		verifier, err := NewTagVerifier(Signing{TrustedKeys: trustedPath})
+		assert.NoError(t, err)
+		assert.NoError(t, verifier(tagObj))
+
+		// Tampering with the message invalidates the signature
+		tagObj.Message = "Release 9.9.9\n"
+		assert.Error(t, verifier(tagObj))
+
+		// An unrelated key is not trusted
+		_, otherPublic := generateSSHKey(t, "")
+		assert.NoError(t, os.WriteFile(trustedPath, ssh.MarshalAuthorizedKey(otherPublic), 0o600))
+		otherVerifier, err := NewTagVerifier(Signing{TrustedKeys: trustedPath})
+		assert.NoError(t, err)
+		tagObj.Message = "Release 0.0.1\n"
+		assert.ErrorContains(t, otherVerifier(tagObj), "untrusted")
+	})
+
+	t.Run("Verifier requires keys", func(t *testing.T) {
+		_, err := NewTagVerifier(Signing{RequireSignedTags: true})
+		assert.Error(t, err)
+	})
+}
+
+func TestListExistingTagsRequireSigned(t *testing.T) {
+	InitLogger(false)
+
+	key, _ := generateSSHKey(t, "")
+	t.Setenv(SigningKeyEnv, string(key))
+	signing := Signing{RequireSignedTags: true}
+	signer, err := LoadSigner(signing)
+	assert.NoError(t, err)
+	verifier, err := NewTagVerifier(signing)
+	assert.NoError(t, err)
+
+	repo := initTestRepository(t, "Initial commit", "Update readme")
+	head, _ := repo.Handler.Head()
+	_, err = repo.Handler.CreateTag("v0.0.3", head.Hash(), nil)
+	assert.NoError(t, err, "Lightweight tag")
+	assert.NoError(t, CreateTag(repo, "v0.0.2", "Release 0.0.2", nil, false))
+	assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", signer, false))
+
+	repo.TagVerifier = verifier
+	ListExistingTags(repo, nil)
+
+	assert.Len(t, repo.Tags, 1, "Only the signed tag should be listed")
+	assert.Equal(t, "v0.0.1", repo.Tags[0].Name)
+}
@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+const (
+	// DefaultTagName is the tag name template used when none is configured
+	DefaultTagName = "v{{ .Version }}"
+	// DefaultTagMessage is the tag message template used when none is configured
+	DefaultTagMessage = "Release {{ .Version }}"
+)
+
+var (
+	// ErrTagExists is returned when the tag to be created already exists
+	ErrTagExists = errors.New("tag already exists")
+	// ErrTagExistsOnRemote is returned when the tag to be pushed already exists on the remote
+	ErrTagExistsOnRemote = errors.New("tag already exists on remote")
+)
+
+// VersionTemplateData holds the values available to the name and message templates
+type VersionTemplateData struct {
+	Version string
+	Major   int
+	Minor   int
+	Patch   int
+	Release int
+}
+
+// RenderVersionTemplate renders a text/template with the semantic version details
+func RenderVersionTemplate(tpl string, semver SemVer) (string, error) {
+	t, err := template.New("version").Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse template %q: %w", tpl, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, VersionTemplateData{
+		Version: FormatSemver(semver),
+		Major:   semver.Major,
+		Minor:   semver.Minor,
+		Patch:   semver.Patch,
+		Release: semver.Release,
+	}); err != nil {
+		return "", fmt.Errorf("unable to render template %q: %w", tpl, err)
+	}
+	return buf.String(), nil
+}
+
+// CreateTag creates an annotated tag pointing to HEAD of the repository, signed when signer is set.
+// Existing tags are never overwritten. With dryRun set, all checks are
+// performed but the tag is not written.
+func CreateTag(repo *GitRepository, name string, message string, signer git.Signer, dryRun bool) error {
+	if repo.Handler == nil {
+		return fmt.Errorf("repository is not prepared")
+	}
+	head, err := repo.Handler.Head()
+	if err != nil {
+		return err
+	}
+	return CreateTagAt(repo, name, head.Hash().String(), message, signer, dryRun)
+}
+
+// CreateTagAt creates an annotated tag pointing to the commit, like CreateTag does for HEAD
+func CreateTagAt(repo *GitRepository, name string, commit string, message string, signer git.Signer, dryRun bool) error {
+	if repo.Handler == nil {
+		return fmt.Errorf("repository is not prepared")
+	}
+
+	if _, err := repo.Handler.Tag(name); err == nil {
+		return fmt.Errorf("%w: %s", ErrTagExists, name)
+	} else if !errors.Is(err, git.ErrTagNotFound) {
+		return err
+	}
+
+	target := plumbing.NewHash(commit)
+	if _, err := repo.Handler.CommitObject(target); err != nil {
+		return fmt.Errorf("unable to find commit %s: %w", commit, err)
+	}
+
+	if dryRun {
+		Info("Dry run, tag not created", map[string]interface{}{
+			"tag":    name,
+			"commit": commit,
+		})
+		return nil
+	}
+
+	var err error
+	if signer != nil {
+		err = createSignedTag(repo, name, target, message, signer)
+	} else {
+		_, err = repo.Handler.CreateTag(name, target, &git.CreateTagOptions{
+			Tagger:  DefaultSignature(repo),
+			Message: message,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	Debug("Created tag", map[string]interface{}{
+		"tag":    name,
+		"commit": commit,
+		"signed": signer != nil,
+	})
+	return nil
+}
+
+// createSignedTag writes an annotated tag object signed with the signer.
+// go-git only signs tags with OpenPGP keys, so the object is built here to support any signer.
+func createSignedTag(repo *GitRepository, name string, target plumbing.Hash, message string, signer git.Signer) error {
+	tagger := DefaultSignature(repo)
+	if tagger == nil {
+		return git.ErrMissingTagger
+	}
+
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     *tagger,
+		Message:    strings.TrimSpace(message) + "\n",
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := tag.EncodeWithoutSignature(unsigned); err != nil {
+		return err
+	}
+	reader, err := unsigned.Reader()
+	if err != nil {
+		return err
+	}
+	signature, err := signer.Sign(reader)
+	if err != nil {
+		return fmt.Errorf("unable to sign tag: %w", err)
+	}
+	tag.PGPSignature = string(signature)
+
+	obj := repo.Handler.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return err
+	}
+	hash, err := repo.Handler.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return err
+	}
+	return repo.Handler.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(name), hash))
+}
+
+// DeleteTag removes the local tag
+func DeleteTag(repo *GitRepository, name string) error {
+	if repo.Handler == nil {
+		return fmt.Errorf("repository is not prepared")
+	}
+	return repo.Handler.DeleteTag(name)
+}
+
+// PushTag pushes the tag to the named remote using the repository credentials.
+// ErrTagExistsOnRemote is returned when the remote already has a tag with this name,
+// which usually means another pipeline released in the meantime.
+func PushTag(repo *GitRepository, remoteName string, name string, dryRun bool) error {
+	remote, auth, err := resolveRemote(repo, remoteName)
+	if err != nil {
+		return err
+	}
+
+	tagRef := plumbing.NewTagReferenceName(name)
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.Name() == tagRef {
+			return fmt.Errorf("%w: %s", ErrTagExistsOnRemote, name)
+		}
+	}
+
+	if dryRun {
+		Info("Dry run, tag not pushed", map[string]interface{}{
+			"tag":    name,
+			"remote": remoteName,
+		})
+		return nil
+	}
+
+	err = repo.Handler.Push(&git.PushOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(tagRef + ":" + tagRef)},
+		Auth:       auth,
+	})
+	if err != nil {
+		// The tag may have been created between listing and pushing
+		if strings.Contains(err.Error(), "non-fast-forward") || strings.Contains(err.Error(), "already exists") {
+			return fmt.Errorf("%w: %s", ErrTagExistsOnRemote, name)
+		}
+		return err
+	}
+
+	Debug("Pushed tag", map[string]interface{}{
+		"tag":    name,
+		"remote": remoteName,
+	})
+	return nil
+}
+
+// FetchTags fetches all tags from the named remote, overwriting local tags of the same name
+func FetchTags(repo *GitRepository, remoteName string) error {
+	_, auth, err := resolveRemote(repo, remoteName)
+	if err != nil {
+		return err
+	}
+
+	err = repo.Handler.Fetch(&git.FetchOptions{
+		RemoteName: remoteName,
+		RefSpecs:   []config.RefSpec{"+refs/tags/*:refs/tags/*"},
+		Auth:       auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+	return nil
+}
+
+// resolveRemote returns the named remote with the credentials for its URL
+func resolveRemote(repo *GitRepository, remoteName string) (*git.Remote, transport.AuthMethod, error) {
+	if repo.Handler == nil {
+		return nil, nil, fmt.Errorf("repository is not prepared")
+	}
+
+	remote, err := repo.Handler.Remote(remoteName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find remote %s: %w", remoteName, err)
+	}
+
+	var auth transport.AuthMethod
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		auth = RepositoryAuth(urls[0])
+	}
+	return remote, auth, nil
+}
+
+// DefaultSignature returns the identity used for tags and commits created by the tool.
+// As with git, the GIT_COMMITTER_NAME / GIT_COMMITTER_EMAIL environment variables take
+// precedence over the repository (global and system) git configuration. Returns nil when
+// no name or email is set, leaving go-git to report the missing identity.
+func DefaultSignature(repo *GitRepository) *object.Signature {
+	name, email := os.Getenv("GIT_COMMITTER_NAME"), os.Getenv("GIT_COMMITTER_EMAIL")
+	if (name == "" || email == "") && repo.Handler != nil {
+		if cfg, err := repo.Handler.ConfigScoped(config.SystemScope); err == nil {
+			if name == "" {
+				name = cfg.User.Name
+			}
+			if email == "" {
+				email = cfg.User.Email
+			}
+		}
+	}
+	if name == "" || email == "" {
+		return nil
+	}
+	return &object.Signature{Name: name, Email: email, When: time.Now()}
+}
@@ -0,0 +1,236 @@
+package utils
+
+import (
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderVersionTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		semver   SemVer
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Default tag name",
+			template: DefaultTagName,
+			semver:   SemVer{Major: 1, Minor: 2, Patch: 3},
+			want:     "v1.2.3",
+		},
+		{
+			name:     "Release candidate",
+			template: "app-{{ .Version }}",
+			semver:   SemVer{Major: 1, Minor: 2, Patch: 3, Release: 4, EnableReleaseCandidate: true},
+			want:     "app-1.2.3-rc.4",
+		},
+		{
+			name:     "Individual fields",
+			template: "{{ .Major }}/{{ .Minor }}/{{ .Patch }}",
+			semver:   SemVer{Major: 1, Minor: 2, Patch: 3},
+			want:     "1/2/3",
+		},
+		{
+			name:     "Invalid template",
+			template: "{{ .Version",
+			wantErr:  true,
+		},
+		{
+			name:     "Unknown field",
+			template: "{{ .Unknown }}",
+			wantErr:  true,
+		},
+	}
+
+	InitLogger(false)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderVersionTemplate(tt.template, tt.semver)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	InitLogger(false)
+
+	t.Run("Creates annotated tag on HEAD", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit", "Update readme")
+
+		assert.NoError(t, CreateTag(repo, "v0.0.2", "Release 0.0.2", nil, false))
+
+		ref, err := repo.Handler.Tag("v0.0.2")
+		assert.NoError(t, err)
+		tagObj, err := repo.Handler.TagObject(ref.Hash())
+		assert.NoError(t, err)
+		head, _ := repo.Handler.Head()
+		assert.Equal(t, head.Hash(), tagObj.Target)
+		assert.Equal(t, "Release 0.0.2\n", tagObj.Message)
+		assert.Equal(t, "Test Author", tagObj.Tagger.Name)
+	})
+
+	t.Run("Refuses to overwrite existing tag", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit")
+
+		assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", nil, false))
+		err := CreateTag(repo, "v0.0.1", "Release 0.0.1", nil, false)
+		assert.ErrorIs(t, err, ErrTagExists)
+		err = CreateTag(repo, "v0.0.1", "Release 0.0.1", nil, true)
+		assert.ErrorIs(t, err, ErrTagExists, "Dry run should report existing tags too")
+	})
+
+	t.Run("Dry run does not create tag", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit")
+
+		assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", nil, true))
+		_, err := repo.Handler.Tag("v0.0.1")
+		assert.Error(t, err, "Tag should not exist after dry run")
+	})
+
+	t.Run("Creates annotated tag on a commit", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit", "Update readme")
+		commits, _ := ListCommits(repo)
+
+		assert.NoError(t, CreateTagAt(repo, "v0.0.1", commits[0].Hash, "Release 0.0.1", nil, false))
+		ref, err := repo.Handler.Tag("v0.0.1")
+		assert.NoError(t, err)
+		tagObj, err := repo.Handler.TagObject(ref.Hash())
+		assert.NoError(t, err)
+		assert.Equal(t, commits[0].Hash, tagObj.Target.String())
+
+		err = CreateTagAt(repo, "v0.0.2", "0123456789012345678901234567890123456789", "Release 0.0.2", nil, false)
+		assert.Error(t, err, "Unknown commit")
+	})
+
+	t.Run("Nil handler", func(t *testing.T) {
+		assert.Error(t, CreateTag(&GitRepository{}, "v0.0.1", "Release 0.0.1", nil, false))
+	})
+}
+
+// initTestRemote creates a bare repository, registers it as the "origin" remote and pushes the branch to it
+func initTestRemote(t *testing.T, repo *GitRepository) *git.Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	bare, err := git.PlainInit(dir, true)
+	if err != nil {
+		t.Fatalf("Failed to init bare repository: %v", err)
+	}
+	if _, err := repo.Handler.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{dir}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+	if err := repo.Handler.Push(&git.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatalf("Failed to push branch: %v", err)
+	}
+	return bare
+}
+
+func TestPushTag(t *testing.T) {
+	InitLogger(false)
+
+	t.Run("Pushes tag to remote", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit")
+		bare := initTestRemote(t, repo)
+
+		assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", nil, false))
+		assert.NoError(t, PushTag(repo, "origin", "v0.0.1", false))
+
+		_, err := bare.Tag("v0.0.1")
+		assert.NoError(t, err, "Tag should exist on remote")
+	})
+
+	t.Run("Dry run does not push", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit")
+		bare := initTestRemote(t, repo)
+
+		assert.NoError(t, CreateTag(repo, "v0.0.1", "Release 0.0.1", nil, false))
+		assert.NoError(t, PushTag(repo, "origin", "v0.0.1", true))
+
+		_, err := bare.Tag("v0.0.1")
+		assert.Error(t, err, "Tag should not exist on remote")
+	})
+
+	t.Run("Reports tag existing on remote", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit", "Update readme")
+		bare := initTestRemote(t, repo)
+
+		head, _ := repo.Handler.Head()
+		_, err := bare.CreateTag("v0.0.2", head.Hash(), &git.CreateTagOptions{
+			Tagger:  &object.Signature{Name: "Other Pipeline", Email: "ci@example.com"},
+			Message: "Release 0.0.2",
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, CreateTag(repo, "v0.0.2", "Release 0.0.2", nil, false))
+		err = PushTag(repo, "origin", "v0.0.2", false)
+		assert.ErrorIs(t, err, ErrTagExistsOnRemote)
+
+		assert.NoError(t, DeleteTag(repo, "v0.0.2"))
+		assert.NoError(t, FetchTags(repo, "origin"))
+		ref, err := repo.Handler.Tag("v0.0.2")
+		assert.NoError(t, err, "Remote tag should be fetched")
+		tagObj, err := repo.Handler.TagObject(ref.Hash())
+		assert.NoError(t, err)
+		assert.Equal(t, "Other Pipeline", tagObj.Tagger.Name)
+	})
+
+	t.Run("Unknown remote", func(t *testing.T) {
+		repo := initTestRepository(t, "Initial commit")
+		assert.Error(t, PushTag(repo, "upstream", "v0.0.1", false))
+	})
+}
+
+func TestRepositoryAuth(t *testing.T) {
+	assert.NotNil(t, RepositoryAuth("https://github.com/lukaszraczylo/semver-generator"))
+	assert.Nil(t, RepositoryAuth("git@github.com:lukaszraczylo/semver-generator.git"))
+	assert.Nil(t, RepositoryAuth("/tmp/repository.git"))
+}
+
+func TestDefaultSignature(t *testing.T) {
+	InitLogger(false)
+	repo := initTestRepository(t, "Initial commit")
+	cfg, err := repo.Handler.Config()
+	assert.NoError(t, err)
+	cfg.User.Name = "Config Author"
+	cfg.User.Email = "config@example.com"
+	assert.NoError(t, repo.Handler.SetConfig(cfg))
+
+	tests := []struct {
+		name      string
+		env       [2]string
+		wantName  string
+		wantEmail string
+	}{
+		{name: "Environment first", env: [2]string{"Env Author", "env@example.com"}, wantName: "Env Author", wantEmail: "env@example.com"},
+		{name: "Email from the configuration", env: [2]string{"Env Author", ""}, wantName: "Env Author", wantEmail: "config@example.com"},
+		{name: "Configuration only", wantName: "Config Author", wantEmail: "config@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GIT_COMMITTER_NAME", tt.env[0])
+			t.Setenv("GIT_COMMITTER_EMAIL", tt.env[1])
+			signature := DefaultSignature(repo)
+			if assert.NotNil(t, signature) {
+				assert.Equal(t, tt.wantName, signature.Name)
+				assert.Equal(t, tt.wantEmail, signature.Email)
+			}
+		})
+	}
+
+	t.Run("No identity", func(t *testing.T) {
+		t.Setenv("GIT_COMMITTER_NAME", "Env Author")
+		t.Setenv("GIT_COMMITTER_EMAIL", "")
+		assert.Nil(t, DefaultSignature(&GitRepository{}))
+	})
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainMismatch describes why the calculated version differs from the expected one:
+// the previous version, the bump each side implies and the commits the calculation is based on
+func ExplainMismatch(report ReleaseReport, expected SemVer, source string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "expected:   %s (%s)\n", FormatSemver(expected), source)
+	fmt.Fprintf(&b, "calculated: %s\n", FormatSemver(report.Version))
+
+	previous := "none"
+	if report.PreviousTag != "" {
+		previous = fmt.Sprintf("%s (%s)", report.PreviousVersionString(), report.PreviousTag)
+	}
+	fmt.Fprintf(&b, "previous:   %s\n", previous)
+
+	expectedBump := CompareBump(report.PreviousVersion, expected)
+	fmt.Fprintf(&b, "bump:       %s, expected %s\n", report.Bump(), expectedBump)
+
+	if len(report.Commits) == 0 {
+		b.WriteString("\nNo commits since the previous version\n")
+		return b.String()
+	}
+
+	b.WriteString("\nCommits since the previous version:\n")
+	var matching []string
+	for i, commit := range report.Commits {
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		fmt.Fprintf(&b, "  %s  %-7s  %s\n", shortHash(commit.Hash), report.Bumps[i], subject)
+		if report.Bumps[i] == expectedBump {
+			matching = append(matching, shortHash(commit.Hash))
+		}
+	}
+
+	switch {
+	case expectedBump > report.Bump() && len(matching) == 0:
+		fmt.Fprintf(&b, "\nNo commit matched a %s keyword, check the wording configuration\n", expectedBump)
+	case expectedBump < report.Bump():
+		fmt.Fprintf(&b, "\nCommits matched %s keywords, a %s release was expected\n", report.Bump(), expectedBump)
+	}
+	return b.String()
+}
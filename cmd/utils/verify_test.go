@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainMismatch(t *testing.T) {
+	InitLogger(false)
+	mockFuzzyFind(t)
+
+	wording := Wording{Patch: []string{"fix"}, Minor: []string{"feat"}}
+	commits := []CommitDetails{{Hash: "0123456789abcdef", Message: "fix: typo\n\nDetails"}}
+	report := NewReleaseReport(SemVer{Minor: 3, Patch: 1}, "v0.3.0", nil, commits, wording, nil, nil)
+
+	explanation := ExplainMismatch(report, SemVer{Minor: 4}, "tag v0.4.0")
+	assert.Contains(t, explanation, "expected:   0.4.0 (tag v0.4.0)\n")
+	assert.Contains(t, explanation, "calculated: 0.3.1\n")
+	assert.Contains(t, explanation, "previous:   0.3.0 (v0.3.0)\n")
+	assert.Contains(t, explanation, "bump:       patch, expected minor\n")
+	assert.Contains(t, explanation, "  0123456  patch    fix: typo\n")
+	assert.Contains(t, explanation, "No commit matched a minor keyword")
+
+	explanation = ExplainMismatch(NewReleaseReport(SemVer{Minor: 3}, "v0.3.0", nil, nil, wording, nil, nil), SemVer{Minor: 3, Patch: 1}, "--expect")
+	assert.Contains(t, explanation, "No commits since the previous version")
+}
@@ -186,21 +186,72 @@ func CheckMatches(content []string, targets []string, blacklist []string) bool {
 	}
 
 	// If we have a match, check against blacklist
-	if hasMatch && len(blacklist) > 0 {
-		for _, blacklistTerm := range blacklist {
-			if strings.Contains(strings.ToLower(contentStr), strings.ToLower(blacklistTerm)) {
-				Debug("Blacklisted term detected, ignoring commit", map[string]interface{}{
-					"content":        contentStr,
-					"blacklist_term": blacklistTerm,
-				})
-				return false
-			}
-		}
+	if hasMatch && IsBlacklisted(contentStr, blacklist) {
+		return false
 	}
 
 	return hasMatch
 }
 
+// CheckPrefixMatches checks if any of the targets is the Conventional Commits type of the subject or of a footer line
+func CheckPrefixMatches(message string, targets []string, blacklist []string) bool {
+	for _, tgt := range targets {
+		if PrefixMatches(message, tgt) {
+			Debug("Found match", map[string]interface{}{
+				"target":  tgt,
+				"content": message,
+			})
+			return !IsBlacklisted(message, blacklist)
+		}
+	}
+	return false
+}
+
+// commitTypeLine matches lines starting with a type, an optional scope and breaking change marker, e.g. "feat(api)!:"
+var commitTypeLine = regexp.MustCompile(`^([^\s():!][^():!]*?)(\([^()]*\))?(!)?:`)
+
+// PrefixMatches reports whether a line of the message starts with the keyword as its type, case insensitive.
+// "fix" matches "fix: typo", "fix(api): typo" and "fix!: typo", "fix!" only the last one, and neither matches "docs: fix typo".
+// Multi-word types such as the "BREAKING CHANGE:" footer are matched the same way.
+func PrefixMatches(message string, keyword string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		match := commitTypeLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		if strings.EqualFold(keyword, match[1]) || match[3] != "" && strings.EqualFold(keyword, match[1]+"!") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingKeywords returns the targets matching the message with the matching mode of the wording
+func matchingKeywords(message string, targets []string, match string) []string {
+	content := strings.Fields(message)
+	var keywords []string
+	for _, tgt := range targets {
+		if match == MatchPrefix && PrefixMatches(message, tgt) || match != MatchPrefix && len(FuzzyFind(tgt, content)) > 0 {
+			keywords = append(keywords, tgt)
+		}
+	}
+	return keywords
+}
+
+// IsBlacklisted reports whether the content contains any of the blacklisted terms (case insensitive)
+func IsBlacklisted(content string, blacklist []string) bool {
+	for _, blacklistTerm := range blacklist {
+		if strings.Contains(strings.ToLower(content), strings.ToLower(blacklistTerm)) {
+			Debug("Blacklisted term detected, ignoring commit", map[string]interface{}{
+				"content":        content,
+				"blacklist_term": blacklistTerm,
+			})
+			return true
+		}
+	}
+	return false
+}
+
 // FuzzyFind is a wrapper for the fuzzy search library to make it easier to mock in tests
 var FuzzyFind = func(needle string, haystack []string) []string {
 	// This will be replaced with the actual implementation in main.go
@@ -278,3 +278,32 @@ func TestCheckMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestPrefixMatches(t *testing.T) {
+	tests := []struct {
+		message string
+		keyword string
+		want    bool
+	}{
+		{"fix: typo", "fix", true},
+		{"Fix(api): typo", "fix", true},
+		{"fix!: drop the v1 API", "fix", true},
+		{"fix!: drop the v1 API", "fix!", true},
+		{"fix(api)!: drop the v1 API", "fix!", true},
+		{"fix: typo", "fix!", false},
+		{"docs: update prefix handling", "fix", false},
+		{"docs(fixtures): update", "fix", false},
+		{"fixed the build", "fix", false},
+		{"docs: fix typo", "fix", false},
+		{"feat: export\n\nBREAKING CHANGE: the CSV format changed", "BREAKING CHANGE", true},
+		{"feat: export\n\nbreaking changes are listed below", "BREAKING CHANGE", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message+"/"+tt.keyword, func(t *testing.T) {
+			assert.Equal(t, tt.want, PrefixMatches(tt.message, tt.keyword))
+		})
+	}
+
+	assert.True(t, CheckPrefixMatches("fix: typo", []string{"feat", "fix"}, nil))
+	assert.False(t, CheckPrefixMatches("fix: Merge branch main", []string{"fix"}, []string{"Merge branch"}))
+}
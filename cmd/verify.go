@@ -0,0 +1,114 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify [flags]",
+	Short: "Verifies a version or tag matches the calculated semantic version",
+	Long: `Calculates the semantic version and compares it with the expected version (--expect 1.4.0),
+	or with the semver tag on a commit (--tag HEAD). Tags on the verified commit are not used as the baseline.
+	Exits with non-zero status and explains the calculation when they disagree.
+	Please refer to documentation on https://github.com/lukaszraczylo/semver-generator for more information.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo.setupCobra()
+		utils.InitLogger(params.varDebug)
+		if err := repo.verify(); err != nil {
+			utils.Critical("Verification failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+	},
+}
+
+// verify calculates the semantic version and compares it with the expected version or tag
+func (s *Setup) verify() error {
+	if (params.varExpect == "") == (params.varVerifyTag == "") {
+		return errors.New("set either --expect or --tag")
+	}
+	if err := s.prepare(); err != nil {
+		return err
+	}
+	s.IgnoreHeadTags = true
+
+	candidates := []string{params.varExpect}
+	source := "--expect"
+	if params.varVerifyTag != "" {
+		commit, err := utils.ResolveRevision(&s.GitRepo, params.varVerifyTag)
+		if err != nil {
+			return err
+		}
+		s.GitRepo.Commit = commit
+		if candidates = s.tagsAt(commit); len(candidates) == 0 {
+			return fmt.Errorf("no semver tag found on %s", params.varVerifyTag)
+		}
+	}
+
+	s.compute()
+	calculated := s.getSemver()
+
+	var expected utils.SemVer
+	for i, candidate := range candidates {
+		if !utils.IsParseableSemverTag(candidate, s.Config.TagPrefixes) {
+			return fmt.Errorf("%s is not a semantic version", candidate)
+		}
+		version := utils.ParseExistingSemver(candidate, utils.SemVer{}, s.Config.TagPrefixes)
+		if utils.FormatSemver(version) == calculated {
+			fmt.Println("VERIFIED", calculated)
+			return nil
+		}
+		if i == 0 {
+			expected = version
+			if params.varVerifyTag != "" {
+				source = "tag " + candidate
+			}
+		}
+	}
+
+	fmt.Print(utils.ExplainMismatch(s.releaseReport(), expected, source))
+	return fmt.Errorf("version mismatch: expected %s, calculated %s", utils.FormatSemver(expected), calculated)
+}
+
+// tagsAt returns the names of the semver tags pointing to the commit
+func (s *Setup) tagsAt(commit string) []string {
+	repository := s.GitRepo
+	repository.Tags, repository.SkippedTags = nil, nil
+	utils.ListExistingTags(&repository, s.Config.TagPrefixes)
+
+	var names []string
+	for _, tag := range repository.Tags {
+		if tag.Hash == commit {
+			names = append(names, tag.Name)
+		}
+	}
+	return names
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&params.varExpect, "expect", "", "Expected version, e.g. 1.4.0 or v1.4.0")
+	verifyCmd.Flags().StringVar(&params.varVerifyTag, "tag", "", "Verify the semver tag on the revision, e.g. HEAD")
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2021 LUKASZ RACZYLO <lukasz$raczylo,com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lukaszraczylo/semver-generator/cmd/utils"
+	assertions "github.com/stretchr/testify/assert"
+)
+
+func TestSetup_verify(t *testing.T) {
+	utils.InitLogger(false)
+
+	originalParams := params
+	defer func() { params = originalParams }()
+	currentDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(currentDir) }()
+
+	repo := initTestRepository(t, "Initial commit", "Update readme", "Update docs")
+	repo.tag("v0.0.1", repo.hashes[0])
+	dir := repo.dir
+	assertions.NoError(t, os.Chdir(dir))
+	missingConfig := filepath.Join(dir, "missing.yaml")
+
+	// Calculated version of HEAD without any tag on it
+	params = myParams{varExisting: true}
+	s := &Setup{UseLocal: true, LocalConfigFile: missingConfig}
+	assertions.NoError(t, s.calculate())
+	calculated := s.getSemver()
+
+	tests := []struct {
+		name    string
+		params  myParams
+		headTag string
+		wantErr bool
+	}{
+		{name: "Expected version matches", params: myParams{varExpect: "v" + calculated}},
+		{name: "Expected version differs", params: myParams{varExpect: "9.0.0"}, wantErr: true},
+		{name: "Expected version is not semver", params: myParams{varExpect: "latest"}, wantErr: true},
+		{name: "Tag on HEAD matches", params: myParams{varVerifyTag: "HEAD"}, headTag: "v" + calculated},
+		{name: "Tag on HEAD differs", params: myParams{varVerifyTag: "HEAD"}, headTag: "v9.0.0", wantErr: true},
+		{name: "No tag on HEAD", params: myParams{varVerifyTag: "HEAD"}, wantErr: true},
+		{name: "Tag on an older commit", params: myParams{varVerifyTag: "HEAD~2"}},
+		{name: "Both flags", params: myParams{varExpect: calculated, varVerifyTag: "HEAD"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.headTag != "" {
+				head, _ := repo.handler.Head()
+				_, err := repo.handler.CreateTag(tt.headTag, head.Hash(), nil)
+				assertions.NoError(t, err)
+				defer func() { _ = repo.handler.DeleteTag(tt.headTag) }()
+			}
+
+			params = tt.params
+			params.varExisting = true
+			s := &Setup{UseLocal: true, LocalConfigFile: missingConfig}
+			err := s.verify()
+			if tt.wantErr {
+				assertions.Error(t, err)
+			} else {
+				assertions.NoError(t, err)
+			}
+		})
+	}
+}
@@ -60,12 +60,14 @@ if [[ ! -z "$INPUT_DEBUGMODE" ]]; then
   echo "----"
   echo "FLAGS: $FLAGS"
   echo "----"
-  /go/src/app/semver-generator generate $FLAGS $*
+  # Step outputs and summary are written by the run below only
+  GITHUB_ACTIONS= /go/src/app/semver-generator generate $FLAGS $*
   echo "----"
 fi
 
 OUT_SEMVER_GEN=$(/go/src/app/semver-generator generate $FLAGS $*)
 [ $? -eq 0 ] || exit 1
-CLEAN_SEMVER=$(echo $OUT_SEMVER_GEN | sed -e 's|SEMVER ||g')
+CLEAN_SEMVER=$(echo "$OUT_SEMVER_GEN" | grep '^SEMVER ' | sed -e 's|SEMVER ||g')
 echo "semantic_version=$CLEAN_SEMVER" >> $GITHUB_OUTPUT
-echo $OUT_SEMVER_GEN
+# Keep the lines intact so the annotations are picked up by the runner
+echo "$OUT_SEMVER_GEN"